@@ -0,0 +1,18 @@
+// Package admin exposes the /admin/* surface for managing the RBAC
+// objects (roles, role bindings) and the identity-provider registry that
+// the auth gear depends on but cannot manage itself.
+package admin
+
+import "net/http"
+
+// DependencyMap provides the admin gear's handlers with their concrete
+// dependencies (stores, audit logger, ...) by name.
+//
+// No concrete implementation is wired up yet: that requires threading a
+// tenant's resolved config, connection pool, and dialect through every
+// gear at once, which is bootstrap work beyond any single request in
+// this series. Each handler factory and its dependency tags are written
+// against the shape that wiring will need.
+type DependencyMap interface {
+	Provide(name string, request *http.Request) interface{}
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/skygeario/skygear-server/pkg/admin"
+	"github.com/skygeario/skygear-server/pkg/core/audit"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+	"github.com/skygeario/skygear-server/pkg/core/handler"
+	"github.com/skygeario/skygear-server/pkg/core/inject"
+	"github.com/skygeario/skygear-server/pkg/core/server"
+	"github.com/skygeario/skygear-server/pkg/core/server/acl"
+)
+
+func AttachACLHandlers(
+	server *server.Server,
+	adminDependency admin.DependencyMap,
+) *server.Server {
+	server.Handle("/admin/acl", &ACLCreateHandlerFactory{adminDependency}).Methods("POST")
+	server.Handle("/admin/acl", &ACLListHandlerFactory{adminDependency}).Methods("GET")
+	server.Handle("/admin/acl/{id}", &ACLDeleteHandlerFactory{adminDependency}).Methods("DELETE")
+	return server
+}
+
+// ACLCreateHandlerFactory / ACLCreateHandler implement POST /admin/acl.
+// The body is an acl.Rule; Scope is always forced to acl.ScopeTenant,
+// since server-scope rules only ever come from the config file.
+type ACLCreateHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f ACLCreateHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &ACLCreateHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f ACLCreateHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type ACLRuleRequestPayload struct {
+	acl.Rule
+	Actor string `json:"-"`
+}
+
+func (p ACLRuleRequestPayload) Validate() error {
+	return p.Rule.Validate()
+}
+
+type ACLRuleResponse struct {
+	Rule acl.Rule `json:"rule"`
+}
+
+type ACLCreateHandler struct {
+	ACLStore    acl.Store     `dependency:"ACLStore"`
+	AuditLogger audit.Logger  `dependency:"AuditLogger"`
+	TxContext   db.TxContext  `dependency:"TxContext"`
+	Logger      *logrus.Entry `dependency:"HandlerLogger"`
+}
+
+func (h ACLCreateHandler) WithTx() bool {
+	return true
+}
+
+func (h ACLCreateHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	payload := ACLRuleRequestPayload{Actor: actorFromRequest(request)}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	payload.Scope = acl.ScopeTenant
+	return payload, nil
+}
+
+func (h ACLCreateHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(ACLRuleRequestPayload)
+
+	if err := h.ACLStore.Create(payload.Rule); err != nil {
+		return nil, err
+	}
+
+	entry, err := newAuditEntry(payload.Actor, "acl.create", payload.ID, nil, payload.Rule)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.AuditLogger.Log(entry); err != nil {
+		return nil, err
+	}
+
+	return ACLRuleResponse{Rule: payload.Rule}, nil
+}
+
+// ACLListHandlerFactory / ACLListHandler implement GET /admin/acl.
+type ACLListHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f ACLListHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &ACLListHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f ACLListHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type ACLListRequestPayload struct{}
+
+func (p ACLListRequestPayload) Validate() error {
+	return nil
+}
+
+type ACLListResponse struct {
+	Rules []acl.Rule `json:"rules"`
+}
+
+type ACLListHandler struct {
+	ACLStore  acl.Store    `dependency:"ACLStore"`
+	TxContext db.TxContext `dependency:"TxContext"`
+}
+
+func (h ACLListHandler) WithTx() bool {
+	return false
+}
+
+func (h ACLListHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	return ACLListRequestPayload{}, nil
+}
+
+func (h ACLListHandler) Handle(req interface{}) (resp interface{}, err error) {
+	rules, err := h.ACLStore.List()
+	if err != nil {
+		return nil, err
+	}
+	return ACLListResponse{Rules: rules}, nil
+}
+
+// ACLDeleteHandlerFactory / ACLDeleteHandler implement
+// DELETE /admin/acl/{id}.
+type ACLDeleteHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f ACLDeleteHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &ACLDeleteHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f ACLDeleteHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type ACLDeleteRequestPayload struct {
+	ID    string `json:"-"`
+	Actor string `json:"-"`
+}
+
+func (p ACLDeleteRequestPayload) Validate() error {
+	if p.ID == "" {
+		return errRuleIDRequired
+	}
+	return nil
+}
+
+type ACLDeleteHandler struct {
+	ACLStore    acl.Store     `dependency:"ACLStore"`
+	AuditLogger audit.Logger  `dependency:"AuditLogger"`
+	TxContext   db.TxContext  `dependency:"TxContext"`
+	Logger      *logrus.Entry `dependency:"HandlerLogger"`
+}
+
+func (h ACLDeleteHandler) WithTx() bool {
+	return true
+}
+
+func (h ACLDeleteHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	return ACLDeleteRequestPayload{
+		ID:    mux.Vars(request)["id"],
+		Actor: actorFromRequest(request),
+	}, nil
+}
+
+func (h ACLDeleteHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(ACLDeleteRequestPayload)
+
+	if err := h.ACLStore.Delete(payload.ID); err != nil {
+		return nil, err
+	}
+
+	entry, err := newAuditEntry(payload.Actor, "acl.delete", payload.ID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.AuditLogger.Log(entry); err != nil {
+		return nil, err
+	}
+
+	return map[string]bool{"ok": true}, nil
+}
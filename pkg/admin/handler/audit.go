@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/skygeario/skygear-server/pkg/core/audit"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authn"
+)
+
+// actorFromRequest identifies who is making the request, for the audit
+// trail: the authenticated AuthInfo's ID, or "master-key" when the
+// request bootstrapped in on the master key alone.
+func actorFromRequest(request *http.Request) string {
+	if info, ok := authn.AuthInfoFromContext(request.Context()); ok {
+		return info.ID
+	}
+	return "master-key"
+}
+
+// newAuditEntry builds an audit.Entry recording actor's action on
+// target, capturing before and after as JSON snapshots so operators
+// have an immutable trail of privilege changes.
+func newAuditEntry(actor string, action string, target string, before interface{}, after interface{}) (audit.Entry, error) {
+	beforeJSON, err := marshalOrEmpty(before)
+	if err != nil {
+		return audit.Entry{}, err
+	}
+	afterJSON, err := marshalOrEmpty(after)
+	if err != nil {
+		return audit.Entry{}, err
+	}
+
+	return audit.Entry{
+		Actor:     actor,
+		Action:    action,
+		Target:    target,
+		Before:    beforeJSON,
+		After:     afterJSON,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func marshalOrEmpty(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
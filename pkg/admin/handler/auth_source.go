@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/skygeario/skygear-server/pkg/admin"
+	"github.com/skygeario/skygear-server/pkg/auth/provider/authsource"
+	"github.com/skygeario/skygear-server/pkg/core/audit"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+	"github.com/skygeario/skygear-server/pkg/core/handler"
+	"github.com/skygeario/skygear-server/pkg/core/inject"
+	"github.com/skygeario/skygear-server/pkg/core/server"
+)
+
+func AttachAuthSourceHandlers(
+	server *server.Server,
+	adminDependency admin.DependencyMap,
+) *server.Server {
+	server.Handle("/admin/auth-sources/{name}", &AuthSourcePutHandlerFactory{adminDependency}).Methods("POST", "PUT")
+	server.Handle("/admin/auth-sources/{name}", &AuthSourceGetHandlerFactory{adminDependency}).Methods("GET")
+	server.Handle("/admin/auth-sources/{name}", &AuthSourceDeleteHandlerFactory{adminDependency}).Methods("DELETE")
+	return server
+}
+
+// AuthSourcePutHandlerFactory / AuthSourcePutHandler implement both
+// POST and PUT /admin/auth-sources/{name}: creating and replacing a
+// registry entry are the same operation, an upsert keyed by name.
+type AuthSourcePutHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f AuthSourcePutHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &AuthSourcePutHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f AuthSourcePutHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type AuthSourcePutRequestPayload struct {
+	Name        string                 `json:"-"`
+	Type        string                 `json:"type"`
+	Settings    map[string]interface{} `json:"settings"`
+	RoleMapping authsource.RoleMapping `json:"role_mapping,omitempty"`
+	Actor       string                 `json:"-"`
+}
+
+func (p AuthSourcePutRequestPayload) Validate() error {
+	if p.Name == "" {
+		return errRoleNameRequired
+	}
+	if p.Type == "" {
+		return errSourceTypeRequired
+	}
+	return nil
+}
+
+type AuthSourceResponse struct {
+	Source authsource.Config `json:"source"`
+}
+
+// secretSettingsKeys names authsource.Config Settings keys known to hold
+// a plaintext secret (an LDAP bind password, an OIDC client secret, ...).
+// DecodeSettings round-trips Settings through encoding/json with no
+// json tags, so these match the source's Settings struct field names
+// exactly.
+var secretSettingsKeys = map[string]bool{
+	"BindPassword": true,
+	"ClientSecret": true,
+}
+
+const redactedSettingValue = "********"
+
+// redactConfig returns a copy of cfg with every known-secret Settings
+// key masked. Settings may carry plaintext secrets that must never reach
+// a permanent audit log or be echoed back verbatim over the API, so
+// every Handle below runs cfg through this before logging or responding
+// with it — the unredacted cfg is only ever passed to RegistryStore.
+func redactConfig(cfg authsource.Config) authsource.Config {
+	if cfg.Settings == nil {
+		return cfg
+	}
+	redacted := make(map[string]interface{}, len(cfg.Settings))
+	for k, v := range cfg.Settings {
+		if secretSettingsKeys[k] {
+			v = redactedSettingValue
+		}
+		redacted[k] = v
+	}
+	cfg.Settings = redacted
+	return cfg
+}
+
+type AuthSourcePutHandler struct {
+	RegistryStore authsource.RegistryStore `dependency:"AuthSourceRegistryStore"`
+	AuditLogger   audit.Logger             `dependency:"AuditLogger"`
+	TxContext     db.TxContext             `dependency:"TxContext"`
+	Logger        *logrus.Entry            `dependency:"HandlerLogger"`
+}
+
+func (h AuthSourcePutHandler) WithTx() bool {
+	return true
+}
+
+func (h AuthSourcePutHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	payload := AuthSourcePutRequestPayload{
+		Name:  mux.Vars(request)["name"],
+		Actor: actorFromRequest(request),
+	}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (h AuthSourcePutHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(AuthSourcePutRequestPayload)
+
+	before, beforeErr := h.RegistryStore.Get(payload.Name)
+
+	cfg := authsource.Config{
+		Name:        payload.Name,
+		Type:        payload.Type,
+		Settings:    payload.Settings,
+		RoleMapping: payload.RoleMapping,
+	}
+	if err := h.RegistryStore.Put(cfg); err != nil {
+		return nil, err
+	}
+
+	var beforeEntry interface{}
+	if beforeErr == nil {
+		beforeEntry = redactConfig(before)
+	}
+	entry, err := newAuditEntry(payload.Actor, "auth_source.put", payload.Name, beforeEntry, redactConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	if err := h.AuditLogger.Log(entry); err != nil {
+		return nil, err
+	}
+
+	return AuthSourceResponse{Source: redactConfig(cfg)}, nil
+}
+
+// AuthSourceGetHandlerFactory / AuthSourceGetHandler implement
+// GET /admin/auth-sources/{name}.
+type AuthSourceGetHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f AuthSourceGetHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &AuthSourceGetHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f AuthSourceGetHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type AuthSourceGetRequestPayload struct {
+	Name string `json:"-"`
+}
+
+func (p AuthSourceGetRequestPayload) Validate() error {
+	if p.Name == "" {
+		return errRoleNameRequired
+	}
+	return nil
+}
+
+type AuthSourceGetHandler struct {
+	RegistryStore authsource.RegistryStore `dependency:"AuthSourceRegistryStore"`
+	TxContext     db.TxContext             `dependency:"TxContext"`
+}
+
+func (h AuthSourceGetHandler) WithTx() bool {
+	return false
+}
+
+func (h AuthSourceGetHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	return AuthSourceGetRequestPayload{Name: mux.Vars(request)["name"]}, nil
+}
+
+func (h AuthSourceGetHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(AuthSourceGetRequestPayload)
+
+	cfg, err := h.RegistryStore.Get(payload.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return AuthSourceResponse{Source: redactConfig(cfg)}, nil
+}
+
+// AuthSourceDeleteHandlerFactory / AuthSourceDeleteHandler implement
+// DELETE /admin/auth-sources/{name}.
+type AuthSourceDeleteHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f AuthSourceDeleteHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &AuthSourceDeleteHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f AuthSourceDeleteHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type AuthSourceDeleteRequestPayload struct {
+	Name  string `json:"-"`
+	Actor string `json:"-"`
+}
+
+func (p AuthSourceDeleteRequestPayload) Validate() error {
+	if p.Name == "" {
+		return errRoleNameRequired
+	}
+	return nil
+}
+
+type AuthSourceDeleteHandler struct {
+	RegistryStore authsource.RegistryStore `dependency:"AuthSourceRegistryStore"`
+	AuditLogger   audit.Logger             `dependency:"AuditLogger"`
+	TxContext     db.TxContext             `dependency:"TxContext"`
+	Logger        *logrus.Entry            `dependency:"HandlerLogger"`
+}
+
+func (h AuthSourceDeleteHandler) WithTx() bool {
+	return true
+}
+
+func (h AuthSourceDeleteHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	return AuthSourceDeleteRequestPayload{
+		Name:  mux.Vars(request)["name"],
+		Actor: actorFromRequest(request),
+	}, nil
+}
+
+func (h AuthSourceDeleteHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(AuthSourceDeleteRequestPayload)
+
+	before, err := h.RegistryStore.Get(payload.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.RegistryStore.Delete(payload.Name); err != nil {
+		return nil, err
+	}
+
+	entry, err := newAuditEntry(payload.Actor, "auth_source.delete", payload.Name, redactConfig(before), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.AuditLogger.Log(entry); err != nil {
+		return nil, err
+	}
+
+	return map[string]bool{"ok": true}, nil
+}
@@ -0,0 +1,11 @@
+package handler
+
+import "errors"
+
+var (
+	errRoleNameRequired    = errors.New("name is required")
+	errNewRoleNameRequired = errors.New("new_name is required")
+	errUserIDRequired      = errors.New("user_id is required")
+	errSourceTypeRequired  = errors.New("type is required")
+	errRuleIDRequired      = errors.New("id is required")
+)
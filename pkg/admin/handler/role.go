@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/skygeario/skygear-server/pkg/admin"
+	"github.com/skygeario/skygear-server/pkg/core/audit"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz/policy"
+	"github.com/skygeario/skygear-server/pkg/core/auth/role"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+	"github.com/skygeario/skygear-server/pkg/core/handler"
+	"github.com/skygeario/skygear-server/pkg/core/inject"
+	"github.com/skygeario/skygear-server/pkg/core/server"
+)
+
+func AttachRoleHandlers(
+	server *server.Server,
+	adminDependency admin.DependencyMap,
+) *server.Server {
+	server.Handle("/admin/roles", &RoleCreateHandlerFactory{adminDependency}).Methods("POST")
+	server.Handle("/admin/roles", &RoleListHandlerFactory{adminDependency}).Methods("GET")
+	server.Handle("/admin/roles", &RoleRenameHandlerFactory{adminDependency}).Methods("PUT")
+	server.Handle("/admin/roles", &RoleDeleteHandlerFactory{adminDependency}).Methods("DELETE")
+	return server
+}
+
+func adminAuthzPolicy() authz.Policy {
+	return policy.AllOf(authz.PolicyFunc(policy.RequireAdminRole))
+}
+
+type RoleResponse struct {
+	Role role.Role `json:"role"`
+}
+
+// RoleCreateHandlerFactory / RoleCreateHandler implement
+// POST /admin/roles: {"name": "moderator"}.
+type RoleCreateHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f RoleCreateHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &RoleCreateHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f RoleCreateHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type RoleCreateRequestPayload struct {
+	Name  string `json:"name"`
+	Actor string `json:"-"`
+}
+
+func (p RoleCreateRequestPayload) Validate() error {
+	if p.Name == "" {
+		return errRoleNameRequired
+	}
+	return nil
+}
+
+type RoleCreateHandler struct {
+	RoleStore   role.Store    `dependency:"RoleStore"`
+	AuditLogger audit.Logger  `dependency:"AuditLogger"`
+	TxContext   db.TxContext  `dependency:"TxContext"`
+	Logger      *logrus.Entry `dependency:"HandlerLogger"`
+}
+
+func (h RoleCreateHandler) WithTx() bool {
+	return true
+}
+
+func (h RoleCreateHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	payload := RoleCreateRequestPayload{Actor: actorFromRequest(request)}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (h RoleCreateHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(RoleCreateRequestPayload)
+
+	if err := h.RoleStore.CreateRole(payload.Name); err != nil {
+		return nil, err
+	}
+
+	created := role.Role{Name: payload.Name}
+	entry, err := newAuditEntry(payload.Actor, "role.create", payload.Name, nil, created)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.AuditLogger.Log(entry); err != nil {
+		return nil, err
+	}
+
+	return RoleResponse{Role: created}, nil
+}
+
+// RoleListHandlerFactory / RoleListHandler implement GET /admin/roles.
+type RoleListHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f RoleListHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &RoleListHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f RoleListHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type RoleListRequestPayload struct{}
+
+func (p RoleListRequestPayload) Validate() error {
+	return nil
+}
+
+type RoleListResponse struct {
+	Roles []role.Role `json:"roles"`
+}
+
+type RoleListHandler struct {
+	RoleStore role.Store   `dependency:"RoleStore"`
+	TxContext db.TxContext `dependency:"TxContext"`
+}
+
+func (h RoleListHandler) WithTx() bool {
+	return false
+}
+
+func (h RoleListHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	return RoleListRequestPayload{}, nil
+}
+
+func (h RoleListHandler) Handle(req interface{}) (resp interface{}, err error) {
+	roles, err := h.RoleStore.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	return RoleListResponse{Roles: roles}, nil
+}
+
+// RoleRenameHandlerFactory / RoleRenameHandler implement PUT
+// /admin/roles: {"name": "old", "new_name": "new"}.
+type RoleRenameHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f RoleRenameHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &RoleRenameHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f RoleRenameHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type RoleRenameRequestPayload struct {
+	Name    string `json:"name"`
+	NewName string `json:"new_name"`
+	Actor   string `json:"-"`
+}
+
+func (p RoleRenameRequestPayload) Validate() error {
+	if p.Name == "" {
+		return errRoleNameRequired
+	}
+	if p.NewName == "" {
+		return errNewRoleNameRequired
+	}
+	return nil
+}
+
+type RoleRenameHandler struct {
+	RoleStore   role.Store    `dependency:"RoleStore"`
+	AuditLogger audit.Logger  `dependency:"AuditLogger"`
+	TxContext   db.TxContext  `dependency:"TxContext"`
+	Logger      *logrus.Entry `dependency:"HandlerLogger"`
+}
+
+func (h RoleRenameHandler) WithTx() bool {
+	return true
+}
+
+func (h RoleRenameHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	payload := RoleRenameRequestPayload{Actor: actorFromRequest(request)}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (h RoleRenameHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(RoleRenameRequestPayload)
+
+	before, err := h.RoleStore.GetRole(payload.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.RoleStore.RenameRole(payload.Name, payload.NewName); err != nil {
+		return nil, err
+	}
+
+	after := role.Role{Name: payload.NewName}
+	entry, err := newAuditEntry(payload.Actor, "role.rename", payload.Name, before, after)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.AuditLogger.Log(entry); err != nil {
+		return nil, err
+	}
+
+	return RoleResponse{Role: after}, nil
+}
+
+// RoleDeleteHandlerFactory / RoleDeleteHandler implement DELETE
+// /admin/roles: {"name": "moderator"}.
+type RoleDeleteHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f RoleDeleteHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &RoleDeleteHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f RoleDeleteHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type RoleDeleteRequestPayload struct {
+	Name  string `json:"name"`
+	Actor string `json:"-"`
+}
+
+func (p RoleDeleteRequestPayload) Validate() error {
+	if p.Name == "" {
+		return errRoleNameRequired
+	}
+	return nil
+}
+
+type RoleDeleteHandler struct {
+	RoleStore   role.Store    `dependency:"RoleStore"`
+	AuditLogger audit.Logger  `dependency:"AuditLogger"`
+	TxContext   db.TxContext  `dependency:"TxContext"`
+	Logger      *logrus.Entry `dependency:"HandlerLogger"`
+}
+
+func (h RoleDeleteHandler) WithTx() bool {
+	return true
+}
+
+func (h RoleDeleteHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	payload := RoleDeleteRequestPayload{Actor: actorFromRequest(request)}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (h RoleDeleteHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(RoleDeleteRequestPayload)
+
+	before, err := h.RoleStore.GetRole(payload.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.RoleStore.DeleteRole(payload.Name); err != nil {
+		return nil, err
+	}
+
+	entry, err := newAuditEntry(payload.Actor, "role.delete", payload.Name, before, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.AuditLogger.Log(entry); err != nil {
+		return nil, err
+	}
+
+	return map[string]bool{"ok": true}, nil
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/skygeario/skygear-server/pkg/admin"
+	"github.com/skygeario/skygear-server/pkg/core/audit"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+	"github.com/skygeario/skygear-server/pkg/core/auth/role"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+	"github.com/skygeario/skygear-server/pkg/core/handler"
+	"github.com/skygeario/skygear-server/pkg/core/inject"
+	"github.com/skygeario/skygear-server/pkg/core/server"
+)
+
+func AttachRoleBindingHandlers(
+	server *server.Server,
+	adminDependency admin.DependencyMap,
+) *server.Server {
+	server.Handle("/admin/roles/{role}/bindings", &RoleBindingCreateHandlerFactory{adminDependency}).Methods("POST")
+	server.Handle("/admin/roles/{role}/bindings", &RoleBindingListHandlerFactory{adminDependency}).Methods("GET")
+	server.Handle("/admin/roles/{role}/bindings", &RoleBindingDeleteHandlerFactory{adminDependency}).Methods("DELETE")
+	return server
+}
+
+// RoleBindingCreateHandlerFactory / RoleBindingCreateHandler implement
+// POST /admin/roles/{role}/bindings: {"user_id": "..."}.
+type RoleBindingCreateHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f RoleBindingCreateHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &RoleBindingCreateHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f RoleBindingCreateHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type RoleBindingRequestPayload struct {
+	Role   string `json:"-"`
+	UserID string `json:"user_id"`
+	Actor  string `json:"-"`
+}
+
+func (p RoleBindingRequestPayload) Validate() error {
+	if p.Role == "" {
+		return errRoleNameRequired
+	}
+	if p.UserID == "" {
+		return errUserIDRequired
+	}
+	return nil
+}
+
+type RoleBindingResponse struct {
+	Role   string `json:"role"`
+	UserID string `json:"user_id"`
+}
+
+type RoleBindingCreateHandler struct {
+	RoleStore   role.Store    `dependency:"RoleStore"`
+	AuditLogger audit.Logger  `dependency:"AuditLogger"`
+	TxContext   db.TxContext  `dependency:"TxContext"`
+	Logger      *logrus.Entry `dependency:"HandlerLogger"`
+}
+
+func (h RoleBindingCreateHandler) WithTx() bool {
+	return true
+}
+
+func (h RoleBindingCreateHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	payload := RoleBindingRequestPayload{
+		Role:  mux.Vars(request)["role"],
+		Actor: actorFromRequest(request),
+	}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (h RoleBindingCreateHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(RoleBindingRequestPayload)
+
+	if err := h.RoleStore.BindRole(payload.UserID, payload.Role); err != nil {
+		return nil, err
+	}
+
+	binding := RoleBindingResponse{Role: payload.Role, UserID: payload.UserID}
+	entry, err := newAuditEntry(payload.Actor, "role_binding.create", payload.Role, nil, binding)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.AuditLogger.Log(entry); err != nil {
+		return nil, err
+	}
+
+	return binding, nil
+}
+
+// RoleBindingListHandlerFactory / RoleBindingListHandler implement GET
+// /admin/roles/{role}/bindings.
+type RoleBindingListHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f RoleBindingListHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &RoleBindingListHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f RoleBindingListHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type RoleBindingListRequestPayload struct {
+	Role string `json:"-"`
+}
+
+func (p RoleBindingListRequestPayload) Validate() error {
+	if p.Role == "" {
+		return errRoleNameRequired
+	}
+	return nil
+}
+
+type RoleBindingListResponse struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+type RoleBindingListHandler struct {
+	RoleStore role.Store   `dependency:"RoleStore"`
+	TxContext db.TxContext `dependency:"TxContext"`
+}
+
+func (h RoleBindingListHandler) WithTx() bool {
+	return false
+}
+
+func (h RoleBindingListHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	return RoleBindingListRequestPayload{Role: mux.Vars(request)["role"]}, nil
+}
+
+func (h RoleBindingListHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(RoleBindingListRequestPayload)
+
+	userIDs, err := h.RoleStore.ListBindings(payload.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return RoleBindingListResponse{UserIDs: userIDs}, nil
+}
+
+// RoleBindingDeleteHandlerFactory / RoleBindingDeleteHandler implement
+// DELETE /admin/roles/{role}/bindings: {"user_id": "..."}.
+type RoleBindingDeleteHandlerFactory struct {
+	Dependency admin.DependencyMap
+}
+
+func (f RoleBindingDeleteHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &RoleBindingDeleteHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f RoleBindingDeleteHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return adminAuthzPolicy()
+}
+
+type RoleBindingDeleteHandler struct {
+	RoleStore   role.Store    `dependency:"RoleStore"`
+	AuditLogger audit.Logger  `dependency:"AuditLogger"`
+	TxContext   db.TxContext  `dependency:"TxContext"`
+	Logger      *logrus.Entry `dependency:"HandlerLogger"`
+}
+
+func (h RoleBindingDeleteHandler) WithTx() bool {
+	return true
+}
+
+func (h RoleBindingDeleteHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	payload := RoleBindingRequestPayload{
+		Role:  mux.Vars(request)["role"],
+		Actor: actorFromRequest(request),
+	}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (h RoleBindingDeleteHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(RoleBindingRequestPayload)
+
+	if err := h.RoleStore.UnbindRole(payload.UserID, payload.Role); err != nil {
+		return nil, err
+	}
+
+	entry, err := newAuditEntry(payload.Actor, "role_binding.delete", payload.Role, RoleBindingResponse{Role: payload.Role, UserID: payload.UserID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.AuditLogger.Log(entry); err != nil {
+		return nil, err
+	}
+
+	return map[string]bool{"ok": true}, nil
+}
@@ -0,0 +1,18 @@
+package authinfo
+
+import "github.com/skygeario/skygear-server/pkg/server/skydb"
+
+// Store looks up and persists skydb.AuthInfo records on behalf of the
+// auth gear's handlers.
+type Store interface {
+	// GetAuthInfoByLoginID resolves the AuthInfo whose AuthData matches
+	// loginID, e.g. a username or email.
+	GetAuthInfoByLoginID(loginID string) (*skydb.AuthInfo, error)
+	// GetAuthInfoByPrincipalID resolves the AuthInfo that has
+	// principalID (a "<source>:<id>" key, see skydb.ProviderInfo) in its
+	// ProviderInfo, e.g. as linked by an authsource.Source login.
+	GetAuthInfoByPrincipalID(principalID string) (*skydb.AuthInfo, error)
+	GetAuthInfo(id string) (*skydb.AuthInfo, error)
+	CreateAuthInfo(info *skydb.AuthInfo) error
+	UpdateAuthInfo(info *skydb.AuthInfo) error
+}
@@ -0,0 +1,13 @@
+package auth
+
+import "net/http"
+
+// DependencyMap provides the auth gear's handlers with their concrete
+// dependencies (stores, token issuer, logger, ...) by name, mirroring
+// record.DependencyMap in the record gear.
+//
+// No concrete implementation is wired up yet: see the equivalent note on
+// admin.DependencyMap.
+type DependencyMap interface {
+	Provide(name string, request *http.Request) interface{}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/skygeario/skygear-server/pkg/auth"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz/policy"
+	"github.com/skygeario/skygear-server/pkg/core/config"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+	"github.com/skygeario/skygear-server/pkg/core/handler"
+	"github.com/skygeario/skygear-server/pkg/core/inject"
+	"github.com/skygeario/skygear-server/pkg/core/server"
+)
+
+func AttachAuthSourcesHandler(
+	server *server.Server,
+	authDependency auth.DependencyMap,
+) *server.Server {
+	server.Handle("/auth/sources", &AuthSourcesHandlerFactory{
+		authDependency,
+	}).Methods("GET")
+	return server
+}
+
+type AuthSourcesHandlerFactory struct {
+	Dependency auth.DependencyMap
+}
+
+func (f AuthSourcesHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &AuthSourcesHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f AuthSourcesHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return policy.AllOf(authz.PolicyFunc(policy.RequireAPIKey))
+}
+
+// AuthSourcesRequestPayload carries the tenant configuration resolved
+// from the request, so Handle never has to reach back into the
+// *http.Request.
+type AuthSourcesRequestPayload struct {
+	TenantConfig config.TenantConfiguration
+}
+
+func (p AuthSourcesRequestPayload) Validate() error {
+	return nil
+}
+
+// AuthSourceDescriptor is the discoverable shape of one configured
+// source: enough for a client to know it exists and how to address it,
+// without leaking its Settings.
+type AuthSourceDescriptor struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type AuthSourcesResponse struct {
+	Sources []AuthSourceDescriptor `json:"sources"`
+}
+
+// AuthSourcesHandler lists the identity-provider sources this tenant
+// has configured, so a client can discover which /auth/login/{source}
+// routes are available without hardcoding them.
+type AuthSourcesHandler struct {
+	TxContext db.TxContext `dependency:"TxContext"`
+}
+
+func (h AuthSourcesHandler) WithTx() bool {
+	return false
+}
+
+func (h AuthSourcesHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	tenantConfig, _ := config.TenantConfigFromContext(request.Context())
+	return AuthSourcesRequestPayload{TenantConfig: tenantConfig}, nil
+}
+
+func (h AuthSourcesHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(AuthSourcesRequestPayload)
+
+	sources := make([]AuthSourceDescriptor, len(payload.TenantConfig.AuthSources))
+	for i, cfg := range payload.TenantConfig.AuthSources {
+		sources[i] = AuthSourceDescriptor{Name: cfg.Name, Type: cfg.Type}
+	}
+
+	return AuthSourcesResponse{Sources: sources}, nil
+}
@@ -0,0 +1,11 @@
+package handler
+
+import "errors"
+
+var (
+	errLoginIDRequired    = errors.New("login_id is required")
+	errPasswordRequired   = errors.New("password is required")
+	errInvalidCredentials = errors.New("invalid login ID or password")
+	errTokenRequired      = errors.New("spec.token is required")
+	errSourceNotFound     = errors.New("auth source not found")
+)
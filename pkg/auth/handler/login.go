@@ -1,43 +1,128 @@
 package handler
 
 import (
-	"context"
-	"fmt"
-	"io/ioutil"
+	"encoding/json"
+	"net/http"
 
-	"github.com/skygeario/skygear-server/pkg/auth/db"
-	"github.com/skygeario/skygear-server/pkg/auth/provider"
-	"github.com/skygeario/skygear-server/pkg/core/config"
+	"github.com/sirupsen/logrus"
+
+	"github.com/skygeario/skygear-server/pkg/auth"
+	"github.com/skygeario/skygear-server/pkg/auth/dependency/authinfo"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz/policy"
+	"github.com/skygeario/skygear-server/pkg/core/auth/role"
+	"github.com/skygeario/skygear-server/pkg/core/auth/token"
+	"github.com/skygeario/skygear-server/pkg/core/db"
 	"github.com/skygeario/skygear-server/pkg/core/handler"
+	"github.com/skygeario/skygear-server/pkg/core/inject"
 	"github.com/skygeario/skygear-server/pkg/core/server"
 )
 
 func AttachLoginHandler(
 	server *server.Server,
-	authDependency provider.AuthProviders,
+	authDependency auth.DependencyMap,
 ) *server.Server {
-	server.Handle("/login", &LoginHandlerFactory{
+	server.Handle("/auth/login", &LoginHandlerFactory{
 		authDependency,
 	}).Methods("POST")
 	return server
 }
 
 type LoginHandlerFactory struct {
-	Dependency provider.AuthProviders
+	Dependency auth.DependencyMap
 }
 
-func (f LoginHandlerFactory) NewHandler(ctx context.Context, tenantConfig config.TenantConfiguration) handler.Handler {
+func (f LoginHandlerFactory) NewHandler(request *http.Request) http.Handler {
 	h := &LoginHandler{}
-	handler.DefaultInject(h, f.Dependency, ctx, tenantConfig)
-	return h
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f LoginHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return policy.AllOf(authz.PolicyFunc(policy.RequireAPIKey))
+}
+
+// LoginRequestPayload is the payload of /auth/login.
+type LoginRequestPayload struct {
+	LoginID  string `json:"login_id"`
+	Password string `json:"password"`
 }
 
-// LoginHandler handles login request
+func (p LoginRequestPayload) Validate() error {
+	if p.LoginID == "" {
+		return errLoginIDRequired
+	}
+	if p.Password == "" {
+		return errPasswordRequired
+	}
+	return nil
+}
+
+// LoginResponse is returned when login_id and password authenticate
+// successfully.
+type LoginResponse struct {
+	AccessToken string   `json:"access_token"`
+	UserID      string   `json:"user_id"`
+	Roles       []string `json:"roles,omitempty"`
+}
+
+// LoginHandler authenticates a user by login ID and password against
+// the stored AuthInfo and, on success, mints a signed access token.
 type LoginHandler struct {
-	DB *db.DBConn `dependency:"DB"`
+	AuthInfoStore authinfo.Store `dependency:"AuthInfoStore"`
+	RoleStore     role.Store     `dependency:"RoleStore"`
+	TokenIssuer   token.Issuer   `dependency:"TokenIssuer"`
+	TxContext     db.TxContext   `dependency:"TxContext"`
+	Logger        *logrus.Entry  `dependency:"HandlerLogger"`
 }
 
-func (h LoginHandler) Handle(ctx handler.Context) {
-	input, _ := ioutil.ReadAll(ctx.Request.Body)
-	fmt.Fprintln(ctx.ResponseWriter, `{"user": "`+h.DB.GetRecord("user:"+string(input))+`"}`)
-}
\ No newline at end of file
+func (h LoginHandler) WithTx() bool {
+	return true
+}
+
+func (h LoginHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	payload := LoginRequestPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (h LoginHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(LoginRequestPayload)
+
+	info, err := h.AuthInfoStore.GetAuthInfoByLoginID(payload.LoginID)
+	if err != nil {
+		return nil, errInvalidCredentials
+	}
+
+	if !info.IsSamePassword(payload.Password) {
+		return nil, errInvalidCredentials
+	}
+
+	// info.Roles is replaced wholesale with RoleStore's current bindings
+	// rather than merged onto whatever was persisted last time: RoleStore
+	// is the sole source of truth for this user's roles, so a binding
+	// removed there (e.g. via the admin API) must actually disappear from
+	// AuthInfo.Roles on the next login instead of surviving forever in an
+	// ever-growing union.
+	boundRoles, err := h.RoleStore.UserRoles(info.ID)
+	if err != nil {
+		return nil, err
+	}
+	info.Roles = boundRoles
+	if err := h.AuthInfoStore.UpdateAuthInfo(info); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := h.TokenIssuer.Issue(*info)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoginResponse{
+		AccessToken: accessToken,
+		UserID:      info.ID,
+		Roles:       info.Roles,
+	}, nil
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/skygeario/skygear-server/pkg/auth"
+	"github.com/skygeario/skygear-server/pkg/auth/dependency/authinfo"
+	"github.com/skygeario/skygear-server/pkg/auth/provider/authsource"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz/policy"
+	"github.com/skygeario/skygear-server/pkg/core/auth/role"
+	"github.com/skygeario/skygear-server/pkg/core/auth/token"
+	"github.com/skygeario/skygear-server/pkg/core/config"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+	"github.com/skygeario/skygear-server/pkg/core/handler"
+	"github.com/skygeario/skygear-server/pkg/core/inject"
+	"github.com/skygeario/skygear-server/pkg/core/server"
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+func AttachSourceLoginHandler(
+	server *server.Server,
+	authDependency auth.DependencyMap,
+) *server.Server {
+	server.Handle("/auth/login/{source}", &SourceLoginHandlerFactory{
+		authDependency,
+	}).Methods("POST")
+	return server
+}
+
+type SourceLoginHandlerFactory struct {
+	Dependency auth.DependencyMap
+}
+
+func (f SourceLoginHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &SourceLoginHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f SourceLoginHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return policy.AllOf(authz.PolicyFunc(policy.RequireAPIKey))
+}
+
+// SourceLoginRequestPayload carries the source name and request context
+// resolved up front, so Handle never has to reach back into the
+// *http.Request. Most Credentials keys are passed through verbatim to
+// the named authsource.Source, whose concrete type determines which it
+// reads (e.g. username/password for ldap, code/code_verifier for oidc);
+// "remote_addr" and "headers" are always overwritten from the live
+// request rather than trusted from the client, and "public_key_pem" is
+// resolved from the claimed principal's stored ProviderInfo rather than
+// trusted from the client — see DecodeRequest.
+type SourceLoginRequestPayload struct {
+	Ctx          context.Context
+	SourceName   string
+	Credentials  authsource.Credentials
+	TenantConfig config.TenantConfiguration
+}
+
+func (p SourceLoginRequestPayload) Validate() error {
+	if p.SourceName == "" {
+		return errSourceNotFound
+	}
+	return nil
+}
+
+type SourceLoginResponse struct {
+	AccessToken string   `json:"access_token"`
+	UserID      string   `json:"user_id"`
+	Roles       []string `json:"roles,omitempty"`
+}
+
+// SourceLoginHandler delegates authentication to the named
+// authsource.Source and mints an access token for the resolved
+// principal, storing its authData in AuthInfo.ProviderInfo under the
+// principal's "<source>:<id>" key.
+type SourceLoginHandler struct {
+	AuthInfoStore authinfo.Store `dependency:"AuthInfoStore"`
+	RoleStore     role.Store     `dependency:"RoleStore"`
+	TokenIssuer   token.Issuer   `dependency:"TokenIssuer"`
+	TxContext     db.TxContext   `dependency:"TxContext"`
+	Logger        *logrus.Entry  `dependency:"HandlerLogger"`
+}
+
+func (h SourceLoginHandler) WithTx() bool {
+	return true
+}
+
+func (h SourceLoginHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	credentials := authsource.Credentials{}
+	if err := json.NewDecoder(request.Body).Decode(&credentials); err != nil {
+		return nil, err
+	}
+
+	sourceName := mux.Vars(request)["source"]
+
+	// remote_addr and headers gate the reverseproxy source's CIDR trust
+	// boundary; they must come from the connection skygear itself
+	// terminated, never from client-supplied JSON, or any client could
+	// claim to be calling from a trusted proxy.
+	credentials["remote_addr"] = request.RemoteAddr
+	credentials["headers"] = map[string][]string(request.Header)
+
+	// public_key_pem gates the httpsig source's signature check; it must
+	// come from the key previously registered for the claimed principal,
+	// never from client-supplied JSON, or any client could sign with a
+	// keypair of its own choosing and authenticate as any key_id.
+	delete(credentials, "public_key_pem")
+	if keyID, ok := credentials["key_id"].(string); ok && keyID != "" {
+		principalID := authsource.PrincipalID(sourceName, keyID)
+		if info, err := h.AuthInfoStore.GetAuthInfoByPrincipalID(principalID); err == nil {
+			if data := info.GetProviderInfoData(principalID); data != nil {
+				if pem, ok := data["public_key_pem"].(string); ok {
+					credentials["public_key_pem"] = pem
+				}
+			}
+		}
+	}
+
+	tenantConfig, _ := config.TenantConfigFromContext(request.Context())
+
+	return SourceLoginRequestPayload{
+		Ctx:          request.Context(),
+		SourceName:   sourceName,
+		Credentials:  credentials,
+		TenantConfig: tenantConfig,
+	}, nil
+}
+
+func (h SourceLoginHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(SourceLoginRequestPayload)
+
+	sourceConfig, ok := findSourceConfig(payload.TenantConfig, payload.SourceName)
+	if !ok {
+		return nil, errSourceNotFound
+	}
+
+	src, err := authsource.New(sourceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	rawPrincipalID, authData, err := src.Authenticate(payload.Ctx, payload.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	principalID := authsource.PrincipalID(payload.SourceName, rawPrincipalID)
+
+	info, err := h.AuthInfoStore.GetAuthInfoByPrincipalID(principalID)
+	if err != nil {
+		newInfo := skydb.NewProviderInfoAuthInfo(principalID, authData)
+		info = &newInfo
+		if err := h.AuthInfoStore.CreateAuthInfo(info); err != nil {
+			return nil, err
+		}
+	} else {
+		info.SetProviderInfoData(principalID, authData)
+	}
+
+	// info.Roles is rebuilt from scratch out of this login's two
+	// authoritative sources — the provider's synced roles and RoleStore's
+	// bindings — rather than unioned onto whatever was persisted last
+	// time. Merging onto the existing value would make Roles grow
+	// monotonically, so a role revoked at the provider or unbound via the
+	// admin API would never actually leave AuthInfo.Roles.
+	syncedRoles, _ := authData["roles"].([]string)
+
+	boundRoles, err := h.RoleStore.UserRoles(info.ID)
+	if err != nil {
+		return nil, err
+	}
+	info.Roles = mergeRoles(syncedRoles, boundRoles)
+
+	if err := h.AuthInfoStore.UpdateAuthInfo(info); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := h.TokenIssuer.Issue(*info)
+	if err != nil {
+		return nil, err
+	}
+
+	return SourceLoginResponse{
+		AccessToken: accessToken,
+		UserID:      info.ID,
+		Roles:       info.Roles,
+	}, nil
+}
+
+func findSourceConfig(tenantConfig config.TenantConfiguration, name string) (authsource.Config, bool) {
+	for _, cfg := range tenantConfig.AuthSources {
+		if cfg.Name == name {
+			return cfg, true
+		}
+	}
+	return authsource.Config{}, false
+}
+
+// mergeRoles returns the de-duplicated union of two role lists that are
+// both authoritative as of the current login (e.g. a provider's synced
+// roles and RoleStore's bindings). It is not meant to merge onto a
+// previously persisted AuthInfo.Roles value, since that would make Roles
+// grow monotonically and defeat revocation.
+func mergeRoles(a []string, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	merged := append([]string{}, a...)
+	for _, role := range a {
+		seen[role] = true
+	}
+	for _, role := range b {
+		if !seen[role] {
+			seen[role] = true
+			merged = append(merged, role)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/skygeario/skygear-server/pkg/auth"
+	"github.com/skygeario/skygear-server/pkg/auth/dependency/authinfo"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz/policy"
+	"github.com/skygeario/skygear-server/pkg/core/auth/token"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+	"github.com/skygeario/skygear-server/pkg/core/handler"
+	"github.com/skygeario/skygear-server/pkg/core/inject"
+	"github.com/skygeario/skygear-server/pkg/core/server"
+)
+
+func AttachTokenReviewHandler(
+	server *server.Server,
+	authDependency auth.DependencyMap,
+) *server.Server {
+	server.Handle("/auth/token/review", &TokenReviewHandlerFactory{
+		authDependency,
+	}).Methods("POST")
+	return server
+}
+
+type TokenReviewHandlerFactory struct {
+	Dependency auth.DependencyMap
+}
+
+func (f TokenReviewHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &TokenReviewHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f TokenReviewHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return policy.AllOf(authz.PolicyFunc(policy.RequireAPIKey))
+}
+
+// TokenReviewRequestPayload mirrors the shape of a Kubernetes
+// TokenReview request.
+type TokenReviewRequestPayload struct {
+	Spec struct {
+		Token string `json:"token"`
+	} `json:"spec"`
+}
+
+func (p TokenReviewRequestPayload) Validate() error {
+	if p.Spec.Token == "" {
+		return errTokenRequired
+	}
+	return nil
+}
+
+// TokenReviewUser is the subset of AuthInfo a reviewer needs to make an
+// authorization decision.
+type TokenReviewUser struct {
+	ID    string   `json:"id"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// TokenReviewStatus reports whether the reviewed token authenticates a
+// user, mirroring a Kubernetes TokenReview response.
+type TokenReviewStatus struct {
+	Authenticated bool             `json:"authenticated"`
+	User          *TokenReviewUser `json:"user,omitempty"`
+}
+
+type TokenReviewResponse struct {
+	Status TokenReviewStatus `json:"status"`
+}
+
+/*
+TokenReviewHandler lets other services validate a skygear access token
+without sharing this tenant's signing secret.
+curl -X POST -H "Content-Type: application/json" \
+  -d @- http://localhost:3000/auth/token/review <<EOF
+{
+	"spec": {
+		"token": "eyJhbGciOi..."
+	}
+}
+EOF
+*/
+type TokenReviewHandler struct {
+	TokenVerifier token.Verifier `dependency:"TokenVerifier"`
+	AuthInfoStore authinfo.Store `dependency:"AuthInfoStore"`
+	TxContext     db.TxContext   `dependency:"TxContext"`
+	Logger        *logrus.Entry  `dependency:"HandlerLogger"`
+}
+
+func (h TokenReviewHandler) WithTx() bool {
+	return false
+}
+
+func (h TokenReviewHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	payload := TokenReviewRequestPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (h TokenReviewHandler) Handle(req interface{}) (resp interface{}, err error) {
+	payload := req.(TokenReviewRequestPayload)
+
+	notAuthenticated := TokenReviewResponse{Status: TokenReviewStatus{Authenticated: false}}
+
+	claims, err := h.TokenVerifier.Verify(payload.Spec.Token)
+	if err != nil {
+		return notAuthenticated, nil
+	}
+
+	info, err := h.AuthInfoStore.GetAuthInfo(claims.Subject)
+	if err != nil {
+		return notAuthenticated, nil
+	}
+
+	if info.TokenValidSince != nil && claims.IssuedAt < info.TokenValidSince.Unix() {
+		return notAuthenticated, nil
+	}
+
+	return TokenReviewResponse{
+		Status: TokenReviewStatus{
+			Authenticated: true,
+			User: &TokenReviewUser{
+				ID:    info.ID,
+				Roles: info.Roles,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,209 @@
+// Package httpsig implements an authsource.Source that authenticates
+// requests signed per RFC 9421 (HTTP Message Signatures), verifying
+// against a public key previously registered for the signing principal
+// in AuthInfo.ProviderInfo.
+//
+// The /auth/login/{source} handler resolves the public key registered
+// for the credentials' key_id through the AuthInfoStore. The signature
+// base is taken from the credentials as the client sent it; it is not
+// rebuilt from the HTTP request. This source only verifies a signature
+// against the key it is handed, and rejects a signature base whose
+// "created" parameter is missing or stale, or whose "expires" parameter
+// has passed, so a captured (signature_base, signature) pair is not a
+// permanent bearer credential.
+package httpsig
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/skygeario/skygear-server/pkg/auth/provider/authsource"
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+// maxSignatureAge bounds how long after its "created" parameter a
+// signature base is still accepted, and clockSkew tolerates the signer's
+// clock running slightly ahead of ours. Together with the "expires"
+// check below, these are what make a captured signature_base/signature
+// pair useless to replay once a short window has passed, rather than a
+// permanent bearer credential.
+const (
+	maxSignatureAge = 5 * time.Minute
+	clockSkew       = 30 * time.Second
+)
+
+// signatureParamsLinePattern matches the RFC 9421 "@signature-params"
+// covered-component line, which carries the signature's created/expires
+// parameters inside the signed data itself — a client cannot alter them
+// without invalidating the signature.
+var signatureParamsLinePattern = regexp.MustCompile(`(?m)^"@signature-params":.*$`)
+var createdParamPattern = regexp.MustCompile(`created=(\d+)`)
+var expiresParamPattern = regexp.MustCompile(`expires=(\d+)`)
+
+func init() {
+	authsource.Register("httpsig", New)
+}
+
+// Settings is the httpsig-specific configuration carried in an
+// authsource.Config's Settings map.
+type Settings struct {
+	// AllowedAlgorithms restricts which RFC 9421 algorithm names are
+	// accepted, e.g. ["ed25519", "rsa-pss-sha512"].
+	AllowedAlgorithms []string
+}
+
+type source struct {
+	name     string
+	settings Settings
+}
+
+// New builds an httpsig authsource.Source from cfg.
+func New(cfg authsource.Config) (authsource.Source, error) {
+	settings := Settings{}
+	if err := authsource.DecodeSettings(cfg.Settings, &settings); err != nil {
+		return nil, err
+	}
+	return &source{name: cfg.Name, settings: settings}, nil
+}
+
+// Authenticate checks that credentials["signature_base"] is fresh (see
+// checkSignatureFreshness), then verifies credentials["signature"]
+// (base64) over it using credentials["public_key_pem"] and
+// credentials["algorithm"], and returns credentials["key_id"] as the
+// principal ID on success.
+func (s *source) Authenticate(ctx context.Context, credentials authsource.Credentials) (string, map[string]interface{}, error) {
+	keyID, _ := credentials["key_id"].(string)
+	algorithm, _ := credentials["algorithm"].(string)
+	signatureBase, _ := credentials["signature_base"].(string)
+	signatureB64, _ := credentials["signature"].(string)
+	publicKeyPEM, _ := credentials["public_key_pem"].(string)
+
+	if keyID == "" || signatureBase == "" || signatureB64 == "" || publicKeyPEM == "" {
+		return "", nil, authsource.ErrInvalidCredentials
+	}
+	if !s.algorithmAllowed(algorithm) {
+		return "", nil, fmt.Errorf("httpsig: algorithm %q not allowed", algorithm)
+	}
+	if err := checkSignatureFreshness(signatureBase, time.Now()); err != nil {
+		return "", nil, err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signatureB64)
+	if err != nil {
+		return "", nil, authsource.ErrInvalidCredentials
+	}
+
+	publicKey, err := parsePublicKey(publicKeyPEM)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := verify(algorithm, publicKey, []byte(signatureBase), signature); err != nil {
+		return "", nil, authsource.ErrInvalidCredentials
+	}
+
+	return keyID, map[string]interface{}{
+		"key_id":    keyID,
+		"algorithm": algorithm,
+	}, nil
+}
+
+// Sync is a no-op: every request re-proves possession of the signing
+// key, so there is no remote state to periodically refresh.
+func (s *source) Sync(ctx context.Context, info *skydb.AuthInfo) error {
+	return nil
+}
+
+func (s *source) algorithmAllowed(algorithm string) bool {
+	if len(s.settings.AllowedAlgorithms) == 0 {
+		return algorithm == "ed25519" || algorithm == "rsa-pss-sha512"
+	}
+	for _, allowed := range s.settings.AllowedAlgorithms {
+		if allowed == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSignatureFreshness rejects a signature base that does not carry a
+// "created" parameter within maxSignatureAge of now, or whose "expires"
+// parameter (if present) has already passed. Without this, a signature
+// base is otherwise just an arbitrary string the server trusts forever:
+// a single captured (signature_base, signature) pair would be a bearer
+// credential good for any number of logins, indefinitely.
+func checkSignatureFreshness(signatureBase string, now time.Time) error {
+	line := signatureParamsLinePattern.FindString(signatureBase)
+	if line == "" {
+		return fmt.Errorf("httpsig: signature base missing @signature-params")
+	}
+
+	createdMatch := createdParamPattern.FindStringSubmatch(line)
+	if createdMatch == nil {
+		return fmt.Errorf("httpsig: signature params missing created")
+	}
+	createdUnix, err := strconv.ParseInt(createdMatch[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("httpsig: invalid created parameter: %w", err)
+	}
+	created := time.Unix(createdUnix, 0)
+	if created.After(now.Add(clockSkew)) {
+		return fmt.Errorf("httpsig: signature created in the future")
+	}
+	if now.Sub(created) > maxSignatureAge {
+		return fmt.Errorf("httpsig: signature too old")
+	}
+
+	if expiresMatch := expiresParamPattern.FindStringSubmatch(line); expiresMatch != nil {
+		expiresUnix, err := strconv.ParseInt(expiresMatch[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("httpsig: invalid expires parameter: %w", err)
+		}
+		if now.After(time.Unix(expiresUnix, 0)) {
+			return fmt.Errorf("httpsig: signature expired")
+		}
+	}
+
+	return nil
+}
+
+func parsePublicKey(pemStr string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemStr))
+	if block == nil {
+		return nil, authsource.ErrInvalidCredentials
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func verify(algorithm string, publicKey crypto.PublicKey, signatureBase []byte, signature []byte) error {
+	switch algorithm {
+	case "ed25519":
+		key, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return authsource.ErrInvalidCredentials
+		}
+		if !ed25519.Verify(key, signatureBase, signature) {
+			return authsource.ErrInvalidCredentials
+		}
+		return nil
+	case "rsa-pss-sha512":
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return authsource.ErrInvalidCredentials
+		}
+		digest := sha512.Sum512(signatureBase)
+		return rsa.VerifyPSS(key, crypto.SHA512, digest[:], signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto, Hash: crypto.SHA512})
+	default:
+		return fmt.Errorf("httpsig: unsupported algorithm %q", algorithm)
+	}
+}
@@ -0,0 +1,59 @@
+package httpsig
+
+import (
+	"testing"
+	"time"
+)
+
+func sigBase(paramsTail string) string {
+	return "\"@method\": POST\n" +
+		"\"@authority\": example.com\n" +
+		"\"@signature-params\": (\"@method\" \"@authority\")" + paramsTail
+}
+
+func TestCheckSignatureFreshnessAcceptsRecentSignature(t *testing.T) {
+	now := time.Unix(1_700_000_300, 0)
+	base := sigBase(";created=1700000000;keyid=\"key1\"")
+	if err := checkSignatureFreshness(base, now); err != nil {
+		t.Errorf("checkSignatureFreshness() = %v, want nil", err)
+	}
+}
+
+func TestCheckSignatureFreshnessRejectsStaleSignature(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0).Add(maxSignatureAge + time.Second)
+	base := sigBase(";created=1700000000;keyid=\"key1\"")
+	if err := checkSignatureFreshness(base, now); err == nil {
+		t.Error("checkSignatureFreshness() = nil, want rejection of a stale signature")
+	}
+}
+
+func TestCheckSignatureFreshnessRejectsExpiredSignature(t *testing.T) {
+	now := time.Unix(1_700_000_200, 0)
+	base := sigBase(";created=1700000000;expires=1700000100;keyid=\"key1\"")
+	if err := checkSignatureFreshness(base, now); err == nil {
+		t.Error("checkSignatureFreshness() = nil, want rejection of an expired signature")
+	}
+}
+
+func TestCheckSignatureFreshnessRejectsMissingCreated(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	base := sigBase(";keyid=\"key1\"")
+	if err := checkSignatureFreshness(base, now); err == nil {
+		t.Error("checkSignatureFreshness() = nil, want rejection when created is absent")
+	}
+}
+
+func TestCheckSignatureFreshnessRejectsMissingSignatureParams(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	if err := checkSignatureFreshness("\"@method\": POST", now); err == nil {
+		t.Error("checkSignatureFreshness() = nil, want rejection when @signature-params is absent")
+	}
+}
+
+func TestCheckSignatureFreshnessRejectsFutureCreated(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	base := sigBase(";created=1700001000;keyid=\"key1\"")
+	if err := checkSignatureFreshness(base, now); err == nil {
+		t.Error("checkSignatureFreshness() = nil, want rejection of a signature created in the future")
+	}
+}
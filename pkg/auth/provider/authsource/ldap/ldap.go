@@ -0,0 +1,172 @@
+// Package ldap implements an authsource.Source that authenticates
+// against an LDAP directory by binding as a service account, searching
+// for the user, and re-binding as the user to verify their password.
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	"gopkg.in/ldap.v3"
+
+	"github.com/skygeario/skygear-server/pkg/auth/provider/authsource"
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+func init() {
+	authsource.Register("ldap", New)
+}
+
+// Settings is the LDAP-specific configuration carried in an
+// authsource.Config's Settings map.
+type Settings struct {
+	URL                   string
+	StartTLS              bool
+	InsecureSkipTLSVerify bool
+	BindDN                string
+	BindPassword          string
+	BaseDN                string
+	// UserFilter is an LDAP filter template with a single %s for the
+	// escaped username, e.g. "(uid=%s)".
+	UserFilter string
+	// GroupAttribute is the user entry attribute holding the groups to
+	// map onto roles, e.g. "memberOf".
+	GroupAttribute string
+}
+
+type source struct {
+	name     string
+	settings Settings
+	roles    authsource.RoleMapping
+}
+
+// New builds an LDAP authsource.Source from cfg.
+func New(cfg authsource.Config) (authsource.Source, error) {
+	settings := Settings{}
+	if err := authsource.DecodeSettings(cfg.Settings, &settings); err != nil {
+		return nil, err
+	}
+	return &source{name: cfg.Name, settings: settings, roles: cfg.RoleMapping}, nil
+}
+
+func (s *source) dial() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: s.settings.InsecureSkipTLSVerify}
+
+	conn, err := ldap.DialURL(s.settings.URL, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.settings.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+func (s *source) Authenticate(ctx context.Context, credentials authsource.Credentials) (string, map[string]interface{}, error) {
+	username, _ := credentials["username"].(string)
+	password, _ := credentials["password"].(string)
+	if username == "" || password == "" {
+		return "", nil, authsource.ErrInvalidCredentials
+	}
+
+	conn, err := s.dial()
+	if err != nil {
+		return "", nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(s.settings.BindDN, s.settings.BindPassword); err != nil {
+		return "", nil, err
+	}
+
+	entry, err := s.findUser(conn, username)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		return "", nil, authsource.ErrInvalidCredentials
+	}
+
+	return entry.DN, authDataFromEntry(entry, s.settings.GroupAttribute, s.roles), nil
+}
+
+func (s *source) Sync(ctx context.Context, info *skydb.AuthInfo) error {
+	dn, _, ok := authsource.FindPrincipal(info, s.name)
+	if !ok {
+		return nil
+	}
+
+	conn, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(s.settings.BindDN, s.settings.BindPassword); err != nil {
+		return err
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, 0, false,
+		"(objectClass=*)", []string{s.settings.GroupAttribute}, nil,
+	))
+	if err != nil {
+		return err
+	}
+	if len(result.Entries) != 1 {
+		return authsource.ErrInvalidCredentials
+	}
+
+	authData := authDataFromEntry(result.Entries[0], s.settings.GroupAttribute, s.roles)
+	info.SetProviderInfoData(authsource.PrincipalID(s.name, dn), authData)
+	info.Roles = mergeRoles(info.Roles, authData["roles"].([]string))
+
+	return nil
+}
+
+func (s *source) findUser(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	result, err := conn.Search(ldap.NewSearchRequest(
+		s.settings.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 1, 0, false,
+		fmt.Sprintf(s.settings.UserFilter, ldap.EscapeFilter(username)),
+		[]string{"dn", s.settings.GroupAttribute},
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) != 1 {
+		return nil, authsource.ErrInvalidCredentials
+	}
+	return result.Entries[0], nil
+}
+
+func authDataFromEntry(entry *ldap.Entry, groupAttribute string, roles authsource.RoleMapping) map[string]interface{} {
+	groups := entry.GetAttributeValues(groupAttribute)
+	return map[string]interface{}{
+		"dn":     entry.DN,
+		"groups": groups,
+		"roles":  roles.Roles(groups),
+	}
+}
+
+func mergeRoles(existing []string, synced []string) []string {
+	seen := make(map[string]bool, len(existing))
+	merged := append([]string{}, existing...)
+	for _, role := range existing {
+		seen[role] = true
+	}
+	for _, role := range synced {
+		if !seen[role] {
+			seen[role] = true
+			merged = append(merged, role)
+		}
+	}
+	return merged
+}
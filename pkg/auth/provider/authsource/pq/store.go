@@ -0,0 +1,105 @@
+package pq
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/skygeario/skygear-server/pkg/auth/provider/authsource"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+)
+
+type registryStore struct {
+	sqlBuilder  db.SQLBuilder
+	sqlExecutor db.SQLExecutor
+	dialect     db.Dialect
+}
+
+// NewRegistryStore returns an authsource.RegistryStore backed by
+// sqlBuilder/sqlExecutor, upserting through dialect's native ON
+// CONFLICT/ON DUPLICATE KEY clause.
+func NewRegistryStore(sqlBuilder db.SQLBuilder, sqlExecutor db.SQLExecutor, dialect db.Dialect) authsource.RegistryStore {
+	return &registryStore{sqlBuilder: sqlBuilder, sqlExecutor: sqlExecutor, dialect: dialect}
+}
+
+func (s *registryStore) List() ([]authsource.Config, error) {
+	rows, err := s.sqlExecutor.QueryWith(
+		s.sqlBuilder.Select("name", "type", "settings", "role_mapping").
+			From(s.sqlBuilder.TableName("_auth_source")),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	configs := []authsource.Config{}
+	for rows.Next() {
+		cfg, err := scanConfig(rows)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, rows.Err()
+}
+
+func (s *registryStore) Get(name string) (authsource.Config, error) {
+	row := s.sqlExecutor.QueryRowWith(
+		s.sqlBuilder.Select("name", "type", "settings", "role_mapping").
+			From(s.sqlBuilder.TableName("_auth_source")).
+			Where("name = ?", name),
+	)
+	return scanConfig(row)
+}
+
+func (s *registryStore) Put(cfg authsource.Config) error {
+	settings, err := json.Marshal(cfg.Settings)
+	if err != nil {
+		return err
+	}
+	roleMapping, err := json.Marshal(cfg.RoleMapping)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Insert(s.sqlBuilder.TableName("_auth_source")).
+			Columns("name", "type", "settings", "role_mapping").
+			Values(cfg.Name, cfg.Type, settings, roleMapping).
+			Suffix(s.dialect.UpsertClause([]string{"name"}, []string{"type", "settings", "role_mapping"})),
+	)
+	return err
+}
+
+func (s *registryStore) Delete(name string) error {
+	_, err := s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Delete(s.sqlBuilder.TableName("_auth_source")).Where("name = ?", name),
+	)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanConfig(row rowScanner) (authsource.Config, error) {
+	var cfg authsource.Config
+	var settingsRaw, roleMappingRaw []byte
+
+	if err := row.Scan(&cfg.Name, &cfg.Type, &settingsRaw, &roleMappingRaw); err != nil {
+		if err == sql.ErrNoRows {
+			return authsource.Config{}, authsource.ErrNotFound
+		}
+		return authsource.Config{}, err
+	}
+
+	if err := json.Unmarshal(settingsRaw, &cfg.Settings); err != nil {
+		return authsource.Config{}, err
+	}
+	if len(roleMappingRaw) > 0 {
+		if err := json.Unmarshal(roleMappingRaw, &cfg.RoleMapping); err != nil {
+			return authsource.Config{}, err
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,16 @@
+package authsource
+
+import "errors"
+
+// ErrNotFound is returned when a named source is not registered.
+var ErrNotFound = errors.New("authsource: not found")
+
+// RegistryStore persists the named Config entries a tenant has
+// registered, so they can be managed through the admin API instead of
+// only a static config file.
+type RegistryStore interface {
+	List() ([]Config, error)
+	Get(name string) (Config, error)
+	Put(cfg Config) error
+	Delete(name string) error
+}
@@ -0,0 +1,130 @@
+// Package reverseproxy implements an authsource.Source that trusts
+// identity headers set by an upstream reverse proxy (e.g. oauth2-proxy)
+// for requests arriving from a configured set of trusted proxy CIDRs.
+package reverseproxy
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/skygeario/skygear-server/pkg/auth/provider/authsource"
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+func init() {
+	authsource.Register("reverseproxy", New)
+}
+
+// Settings is the reverse-proxy-specific configuration carried in an
+// authsource.Config's Settings map.
+type Settings struct {
+	// UserHeader carries the authenticated principal's ID, e.g.
+	// "X-Auth-Request-User".
+	UserHeader string
+	// GroupsHeader, if set, carries a comma-separated list of the
+	// principal's remote group memberships.
+	GroupsHeader string
+	// TrustedCIDRs lists the CIDR blocks the proxy itself is allowed to
+	// connect from; requests whose remote address falls outside every
+	// block are rejected regardless of what the headers claim.
+	TrustedCIDRs []string
+}
+
+type source struct {
+	name     string
+	settings Settings
+	nets     []*net.IPNet
+	roles    authsource.RoleMapping
+}
+
+// New builds a reverse-proxy authsource.Source from cfg.
+func New(cfg authsource.Config) (authsource.Source, error) {
+	settings := Settings{}
+	if err := authsource.DecodeSettings(cfg.Settings, &settings); err != nil {
+		return nil, err
+	}
+
+	nets := make([]*net.IPNet, 0, len(settings.TrustedCIDRs))
+	for _, cidr := range settings.TrustedCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, ipNet)
+	}
+
+	return &source{name: cfg.Name, settings: settings, nets: nets, roles: cfg.RoleMapping}, nil
+}
+
+// Authenticate trusts the principal ID and group headers only if
+// credentials["remote_addr"] falls within a configured trusted CIDR;
+// the caller (the /auth/login/{source} handler) is responsible for
+// populating credentials from the live request.
+func (s *source) Authenticate(ctx context.Context, credentials authsource.Credentials) (string, map[string]interface{}, error) {
+	remoteAddr, _ := credentials["remote_addr"].(string)
+	if !s.isTrusted(remoteAddr) {
+		return "", nil, authsource.ErrInvalidCredentials
+	}
+
+	headers, _ := credentials["headers"].(map[string][]string)
+	userID := firstHeader(headers, s.settings.UserHeader)
+	if userID == "" {
+		return "", nil, authsource.ErrInvalidCredentials
+	}
+
+	groups := splitCSV(firstHeader(headers, s.settings.GroupsHeader))
+
+	return userID, map[string]interface{}{
+		"user":   userID,
+		"groups": groups,
+		"roles":  s.roles.Roles(groups),
+	}, nil
+}
+
+// Sync is a no-op: a reverse proxy only ever asserts identity on the
+// request it authenticated, there is nothing to refresh out of band.
+func (s *source) Sync(ctx context.Context, info *skydb.AuthInfo) error {
+	return nil
+}
+
+func (s *source) isTrusted(remoteAddr string) bool {
+	host := remoteAddr
+	if h, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		host = h
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, ipNet := range s.nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstHeader(headers map[string][]string, name string) string {
+	if name == "" {
+		return ""
+	}
+	values := headers[name]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
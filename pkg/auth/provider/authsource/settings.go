@@ -0,0 +1,14 @@
+package authsource
+
+import "encoding/json"
+
+// DecodeSettings unmarshals settings into out via a JSON round-trip, so
+// a concrete source can declare a typed settings struct instead of
+// digging through the map by hand.
+func DecodeSettings(settings map[string]interface{}, out interface{}) error {
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
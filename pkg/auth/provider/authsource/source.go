@@ -0,0 +1,113 @@
+// Package authsource lets the auth gear authenticate principals against
+// any number of external identity providers (LDAP, OIDC, a trusted
+// reverse proxy, HTTP Message Signatures, ...) behind one interface,
+// instead of the single opaque provider.AuthProviders dependency the
+// gear used to hardcode.
+package authsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+// ErrInvalidCredentials is returned by Authenticate when credentials do
+// not resolve to a principal known to the source.
+var ErrInvalidCredentials = errors.New("authsource: invalid credentials")
+
+// Credentials carries whatever a Source needs to authenticate a
+// principal: a login/password pair, request headers, a bearer
+// assertion, etc. Each Source interprets the keys it cares about and
+// ignores the rest.
+type Credentials map[string]interface{}
+
+// Source authenticates principals against an external identity provider
+// and keeps AuthInfo.ProviderInfo in sync with it.
+type Source interface {
+	// Authenticate resolves credentials to a principal ID (unique within
+	// this source, e.g. an LDAP DN or an OIDC subject) and the authData
+	// that should be stored against it in AuthInfo.ProviderInfo.
+	Authenticate(ctx context.Context, credentials Credentials) (principalID string, authData map[string]interface{}, err error)
+
+	// Sync refreshes info's ProviderInfo entry for this source in place,
+	// e.g. to pick up changed group memberships, without requiring the
+	// user to log in again. Sync is a no-op if info has no entry for
+	// this source.
+	Sync(ctx context.Context, info *skydb.AuthInfo) error
+}
+
+// RoleMapping maps a remote group or claim value onto a local role
+// name, so RBAC works the same regardless of where a principal
+// authenticated.
+type RoleMapping map[string]string
+
+// Roles returns the distinct local roles that remoteGroups map to,
+// dropping any group with no configured mapping.
+func (m RoleMapping) Roles(remoteGroups []string) []string {
+	seen := make(map[string]bool, len(remoteGroups))
+	roles := []string{}
+	for _, group := range remoteGroups {
+		role, ok := m[group]
+		if !ok || seen[role] {
+			continue
+		}
+		seen[role] = true
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+// Config is a tenant-configured named auth source: Name is how it is
+// addressed (e.g. in /auth/login/{source}), Type selects which
+// registered Factory builds it, and Settings carries the
+// source-specific configuration.
+type Config struct {
+	Name        string                 `json:"name" yaml:"name"`
+	Type        string                 `json:"type" yaml:"type"`
+	Settings    map[string]interface{} `json:"settings" yaml:"settings"`
+	RoleMapping RoleMapping            `json:"role_mapping,omitempty" yaml:"role_mapping,omitempty"`
+}
+
+// Factory builds a Source from its tenant configuration.
+type Factory func(cfg Config) (Source, error)
+
+var factories = map[string]Factory{}
+
+// Register makes a Source type available under sourceType for New to
+// construct. Concrete source packages call Register from an init
+// function.
+func Register(sourceType string, factory Factory) {
+	factories[sourceType] = factory
+}
+
+// New constructs the Source described by cfg using the Factory
+// registered for cfg.Type.
+func New(cfg Config) (Source, error) {
+	factory, ok := factories[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("authsource: unknown source type %q", cfg.Type)
+	}
+	return factory(cfg)
+}
+
+// PrincipalID builds the composite ProviderInfo key for a principal
+// authenticated through the named source, matching the "<provider>:<id>"
+// convention documented on skydb.ProviderInfo.
+func PrincipalID(sourceName, rawID string) string {
+	return sourceName + ":" + rawID
+}
+
+// FindPrincipal returns the raw principal ID and authData previously
+// stored against info for sourceName, if any.
+func FindPrincipal(info *skydb.AuthInfo, sourceName string) (rawID string, authData map[string]interface{}, ok bool) {
+	prefix := sourceName + ":"
+	for key, data := range info.ProviderInfo {
+		if strings.HasPrefix(key, prefix) {
+			return strings.TrimPrefix(key, prefix), data, true
+		}
+	}
+	return "", nil, false
+}
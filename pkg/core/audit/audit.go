@@ -0,0 +1,23 @@
+// Package audit records an immutable trail of privilege-affecting admin
+// mutations, so operators can always answer "who changed this, and to
+// what".
+package audit
+
+import "time"
+
+// Entry is one audit record: who did what to what, and what it looked
+// like before and after. Before/After are opaque JSON snapshots of the
+// affected object, so any admin resource can be logged uniformly.
+type Entry struct {
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target"`
+	Before    string    `json:"before,omitempty"`
+	After     string    `json:"after,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Logger appends Entries to the immutable audit trail.
+type Logger interface {
+	Log(entry Entry) error
+}
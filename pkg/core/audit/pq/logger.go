@@ -0,0 +1,27 @@
+package pq
+
+import (
+	"github.com/skygeario/skygear-server/pkg/core/audit"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+)
+
+type logger struct {
+	sqlBuilder  db.SQLBuilder
+	sqlExecutor db.SQLExecutor
+}
+
+// NewLogger returns an audit.Logger backed by sqlBuilder/sqlExecutor, so
+// an audit entry is written in the same transaction as the mutation it
+// records.
+func NewLogger(sqlBuilder db.SQLBuilder, sqlExecutor db.SQLExecutor) audit.Logger {
+	return &logger{sqlBuilder: sqlBuilder, sqlExecutor: sqlExecutor}
+}
+
+func (l *logger) Log(entry audit.Entry) error {
+	_, err := l.sqlExecutor.ExecWith(
+		l.sqlBuilder.Insert(l.sqlBuilder.TableName("_audit_log")).
+			Columns("actor", "action", "target", "before", "after", "created_at").
+			Values(entry.Actor, entry.Action, entry.Target, entry.Before, entry.After, entry.CreatedAt),
+	)
+	return err
+}
@@ -0,0 +1,24 @@
+package authn
+
+import (
+	"context"
+
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+type contextKeyType struct{}
+
+var contextKey = contextKeyType{}
+
+// WithAuthInfo returns a copy of ctx carrying the AuthInfo resolved for
+// the current request.
+func WithAuthInfo(ctx context.Context, info skydb.AuthInfo) context.Context {
+	return context.WithValue(ctx, contextKey, info)
+}
+
+// AuthInfoFromContext returns the AuthInfo resolved for the current
+// request by Middleware, if any.
+func AuthInfoFromContext(ctx context.Context) (skydb.AuthInfo, bool) {
+	info, ok := ctx.Value(contextKey).(skydb.AuthInfo)
+	return info, ok
+}
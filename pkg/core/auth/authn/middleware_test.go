@@ -0,0 +1,118 @@
+package authn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/skygeario/skygear-server/pkg/core/auth/token"
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+type stubVerifier struct {
+	claims *token.Claims
+	err    error
+}
+
+func (v stubVerifier) Verify(tokenString string) (*token.Claims, error) {
+	return v.claims, v.err
+}
+
+type stubAuthInfoStore struct {
+	info *skydb.AuthInfo
+	err  error
+}
+
+func (s stubAuthInfoStore) GetAuthInfoByLoginID(loginID string) (*skydb.AuthInfo, error) {
+	return nil, s.err
+}
+func (s stubAuthInfoStore) GetAuthInfoByPrincipalID(principalID string) (*skydb.AuthInfo, error) {
+	return nil, s.err
+}
+func (s stubAuthInfoStore) GetAuthInfo(id string) (*skydb.AuthInfo, error) {
+	return s.info, s.err
+}
+func (s stubAuthInfoStore) CreateAuthInfo(info *skydb.AuthInfo) error { return nil }
+func (s stubAuthInfoStore) UpdateAuthInfo(info *skydb.AuthInfo) error { return nil }
+
+func newRequestWithToken(tokenString string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if tokenString != "" {
+		r.Header.Set("Authorization", "Bearer "+tokenString)
+	}
+	return r
+}
+
+func TestMiddlewareInjectsAuthInfoForValidToken(t *testing.T) {
+	validSince := time.Now().UTC().Add(-time.Hour)
+	info := &skydb.AuthInfo{ID: "user-1", TokenValidSince: &validSince}
+
+	m := Middleware{
+		TokenVerifier: stubVerifier{claims: &token.Claims{
+			StandardClaims: jwt.StandardClaims{Subject: "user-1", IssuedAt: validSince.Add(time.Minute).Unix()},
+		}},
+		AuthInfoStore: stubAuthInfoStore{info: info},
+	}
+
+	var injected bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, injected = AuthInfoFromContext(r.Context())
+	})
+
+	m.Handle(next).ServeHTTP(httptest.NewRecorder(), newRequestWithToken("irrelevant"))
+
+	if !injected {
+		t.Error("expected AuthInfo to be injected for a token issued after TokenValidSince")
+	}
+}
+
+func TestMiddlewareRejectsTokenIssuedBeforeTokenValidSince(t *testing.T) {
+	// TokenValidSince moved forward (e.g. the password changed) after
+	// this token was issued, so it must be treated as expired even
+	// though its signature still verifies.
+	validSince := time.Now().UTC()
+	issuedAt := validSince.Add(-time.Minute).Unix()
+	info := &skydb.AuthInfo{ID: "user-1", TokenValidSince: &validSince}
+
+	m := Middleware{
+		TokenVerifier: stubVerifier{claims: &token.Claims{
+			StandardClaims: jwt.StandardClaims{Subject: "user-1", IssuedAt: issuedAt},
+		}},
+		AuthInfoStore: stubAuthInfoStore{info: info},
+	}
+
+	var injected bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, injected = AuthInfoFromContext(r.Context())
+	})
+
+	m.Handle(next).ServeHTTP(httptest.NewRecorder(), newRequestWithToken("irrelevant"))
+
+	if injected {
+		t.Error("expected AuthInfo not to be injected for a token issued before TokenValidSince")
+	}
+}
+
+func TestMiddlewarePassesThroughWithNoToken(t *testing.T) {
+	m := Middleware{
+		TokenVerifier: stubVerifier{err: nil},
+		AuthInfoStore: stubAuthInfoStore{},
+	}
+
+	var called bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if _, ok := AuthInfoFromContext(r.Context()); ok {
+			t.Error("expected no AuthInfo in context when no token is present")
+		}
+	})
+
+	m.Handle(next).ServeHTTP(httptest.NewRecorder(), newRequestWithToken(""))
+
+	if !called {
+		t.Error("expected next handler to still run when no token is present")
+	}
+}
@@ -0,0 +1,16 @@
+package authz
+
+import "net/http"
+
+// Policy determines whether a request is authorized to reach a handler.
+type Policy interface {
+	IsAllowed(r *http.Request) error
+}
+
+// PolicyFunc adapts an ordinary function to a Policy.
+type PolicyFunc func(r *http.Request) error
+
+// IsAllowed calls f.
+func (f PolicyFunc) IsAllowed(r *http.Request) error {
+	return f(r)
+}
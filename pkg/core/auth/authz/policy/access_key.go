@@ -0,0 +1,41 @@
+package policy
+
+import (
+	"net/http"
+
+	"github.com/skygeario/skygear-server/pkg/core/config"
+)
+
+// RequireMasterKey allows the request only if it carries the tenant's
+// master key in the X-Skygear-Api-Key header.
+func RequireMasterKey(r *http.Request) error {
+	return requireAccessKey(r, func(c config.TenantConfiguration) string {
+		return c.MasterKey
+	})
+}
+
+// RequireAPIKey allows the request if it carries the tenant's API key,
+// or its master key, since the master key satisfies every lower
+// privilege check.
+func RequireAPIKey(r *http.Request) error {
+	if RequireMasterKey(r) == nil {
+		return nil
+	}
+	return requireAccessKey(r, func(c config.TenantConfiguration) string {
+		return c.APIKey
+	})
+}
+
+func requireAccessKey(r *http.Request, expected func(config.TenantConfiguration) string) error {
+	tenantConfig, ok := config.TenantConfigFromContext(r.Context())
+	if !ok {
+		return ErrNotAuthenticated
+	}
+
+	key := expected(tenantConfig)
+	if key == "" || r.Header.Get("X-Skygear-Api-Key") != key {
+		return ErrAccessKeyNotAccepted
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/skygeario/skygear-server/pkg/core/auth/authn"
+	"github.com/skygeario/skygear-server/pkg/core/config"
+)
+
+// ErrNotAdmin is returned when an authenticated user holds none of the
+// tenant's configured admin_roles.
+var ErrNotAdmin = errors.New("policy: not an admin")
+
+// RequireAdminRole allows the request if it carries the tenant's master
+// key (so the very first admin can always bootstrap the RBAC objects
+// this policy itself depends on), or an access token whose AuthInfo has
+// one of the roles listed in the tenant's admin_roles.
+func RequireAdminRole(r *http.Request) error {
+	if RequireMasterKey(r) == nil {
+		return nil
+	}
+
+	info, ok := authn.AuthInfoFromContext(r.Context())
+	if !ok {
+		return ErrNotAuthenticated
+	}
+
+	tenantConfig, ok := config.TenantConfigFromContext(r.Context())
+	if !ok {
+		return ErrNotAuthenticated
+	}
+
+	if info.HasAnyRoles(tenantConfig.AdminRoles) {
+		return nil
+	}
+
+	return ErrNotAdmin
+}
@@ -0,0 +1,16 @@
+package policy
+
+import (
+	"net/http"
+
+	"github.com/skygeario/skygear-server/pkg/core/auth/authn"
+)
+
+// RequireAuthenticated allows the request only if authn.Middleware
+// resolved an AuthInfo for it, e.g. from a valid Bearer access token.
+func RequireAuthenticated(r *http.Request) error {
+	if _, ok := authn.AuthInfoFromContext(r.Context()); !ok {
+		return ErrNotAuthenticated
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package policy
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+)
+
+// ErrNotAuthenticated is returned when a request carries none of a
+// master key, an API key, or a valid access token.
+var ErrNotAuthenticated = errors.New("authentication required")
+
+// ErrAccessKeyNotAccepted is returned when a request carries a master
+// key or API key that does not match the tenant configuration.
+var ErrAccessKeyNotAccepted = errors.New("access key not accepted")
+
+// AllOf builds a Policy that allows the request only if every one of
+// policies allows it.
+func AllOf(policies ...authz.Policy) authz.Policy {
+	return authz.PolicyFunc(func(r *http.Request) error {
+		for _, p := range policies {
+			if err := p.IsAllowed(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AnyOf builds a Policy that allows the request if at least one of
+// policies allows it, returning the last error seen otherwise.
+func AnyOf(policies ...authz.Policy) authz.Policy {
+	return authz.PolicyFunc(func(r *http.Request) error {
+		lastErr := ErrNotAuthenticated
+		for _, p := range policies {
+			if err := p.IsAllowed(r); err == nil {
+				return nil
+			} else {
+				lastErr = err
+			}
+		}
+		return lastErr
+	})
+}
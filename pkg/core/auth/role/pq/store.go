@@ -0,0 +1,154 @@
+package pq
+
+import (
+	"database/sql"
+
+	"github.com/skygeario/skygear-server/pkg/core/auth/role"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+)
+
+type roleStore struct {
+	sqlBuilder  db.SQLBuilder
+	sqlExecutor db.SQLExecutor
+}
+
+// NewRoleStore returns a role.Store backed by sqlBuilder/sqlExecutor, so
+// role and role-binding writes participate in the same transaction as
+// the rest of a request.
+func NewRoleStore(sqlBuilder db.SQLBuilder, sqlExecutor db.SQLExecutor) role.Store {
+	return &roleStore{sqlBuilder: sqlBuilder, sqlExecutor: sqlExecutor}
+}
+
+func (s *roleStore) UserRoles(userID string) ([]string, error) {
+	rows, err := s.sqlExecutor.QueryWith(
+		s.sqlBuilder.Select("role").
+			From(s.sqlBuilder.TableName("_role_binding")).
+			Where("user_id = ?", userID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanStrings(rows)
+}
+
+func (s *roleStore) CreateRole(name string) error {
+	_, err := s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Insert(s.sqlBuilder.TableName("_role")).
+			Columns("id").
+			Values(name),
+	)
+	return err
+}
+
+func (s *roleStore) GetRole(name string) (role.Role, error) {
+	row := s.sqlExecutor.QueryRowWith(
+		s.sqlBuilder.Select("id").
+			From(s.sqlBuilder.TableName("_role")).
+			Where("id = ?", name),
+	)
+
+	var id string
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return role.Role{}, role.ErrNotFound
+		}
+		return role.Role{}, err
+	}
+	return role.Role{Name: id}, nil
+}
+
+func (s *roleStore) ListRoles() ([]role.Role, error) {
+	rows, err := s.sqlExecutor.QueryWith(
+		s.sqlBuilder.Select("id").From(s.sqlBuilder.TableName("_role")),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []role.Role{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role.Role{Name: id})
+	}
+	return roles, rows.Err()
+}
+
+func (s *roleStore) RenameRole(name string, newName string) error {
+	result, err := s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Update(s.sqlBuilder.TableName("_role")).
+			Set("id", newName).
+			Where("id = ?", name),
+	)
+	if err != nil {
+		return err
+	}
+	return errIfNotFound(result)
+}
+
+func (s *roleStore) DeleteRole(name string) error {
+	result, err := s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Delete(s.sqlBuilder.TableName("_role")).Where("id = ?", name),
+	)
+	if err != nil {
+		return err
+	}
+	return errIfNotFound(result)
+}
+
+func (s *roleStore) BindRole(userID string, roleName string) error {
+	_, err := s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Insert(s.sqlBuilder.TableName("_role_binding")).
+			Columns("user_id", "role").
+			Values(userID, roleName),
+	)
+	return err
+}
+
+func (s *roleStore) UnbindRole(userID string, roleName string) error {
+	_, err := s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Delete(s.sqlBuilder.TableName("_role_binding")).
+			Where("user_id = ? AND role = ?", userID, roleName),
+	)
+	return err
+}
+
+func (s *roleStore) ListBindings(roleName string) ([]string, error) {
+	rows, err := s.sqlExecutor.QueryWith(
+		s.sqlBuilder.Select("user_id").
+			From(s.sqlBuilder.TableName("_role_binding")).
+			Where("role = ?", roleName),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanStrings(rows)
+}
+
+func scanStrings(rows *sql.Rows) ([]string, error) {
+	values := []string{}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+func errIfNotFound(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return role.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Package role manages the roles that skydb.AuthInfo.Roles references,
+// and their bindings to users.
+package role
+
+import "errors"
+
+// ErrNotFound is returned when a named role does not exist.
+var ErrNotFound = errors.New("role: not found")
+
+// Role is a named grant that can be bound to users and referenced by
+// field and record ACLs (e.g. "_role:admin").
+type Role struct {
+	Name string `json:"name"`
+}
+
+// Store manages roles and their bindings to users.
+type Store interface {
+	// UserRoles returns the roles bound to userID, used by field- and
+	// record-level ACL evaluation to resolve "_role:<role>" references.
+	UserRoles(userID string) ([]string, error)
+
+	CreateRole(name string) error
+	GetRole(name string) (Role, error)
+	ListRoles() ([]Role, error)
+	RenameRole(name string, newName string) error
+	DeleteRole(name string) error
+
+	BindRole(userID string, roleName string) error
+	UnbindRole(userID string, roleName string) error
+	ListBindings(roleName string) ([]string, error)
+}
@@ -0,0 +1,115 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/skygeario/skygear-server/pkg/core/config"
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+// Claims are the JWT claims carried by an access token minted for an
+// authenticated AuthInfo. TokenValidSince records the AuthInfo's
+// TokenValidSince at the time the token was issued, so a service that
+// only has the token (and not a DB connection) can still reason about
+// it; Middleware additionally re-checks against the AuthInfo's current
+// TokenValidSince on every request, since it may have moved forward
+// since this token was minted.
+type Claims struct {
+	jwt.StandardClaims
+	Roles           []string `json:"roles,omitempty"`
+	TokenValidSince int64    `json:"token_valid_since,omitempty"`
+}
+
+// Issuer mints signed access tokens for an AuthInfo.
+type Issuer interface {
+	Issue(info skydb.AuthInfo) (string, error)
+}
+
+// Verifier validates a signed access token and returns its claims.
+type Verifier interface {
+	Verify(tokenString string) (*Claims, error)
+}
+
+// Factory issues and verifies access tokens according to a tenant's
+// TokenSigningConfiguration. It supports HMAC (HS256) and RSA (RS256)
+// signing so that multi-service deployments can validate tokens without
+// sharing a secret.
+type Factory struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	issuer    string
+}
+
+// NewFactory builds a Factory from a tenant's TokenSigningConfiguration.
+func NewFactory(cfg config.TokenSigningConfiguration) (*Factory, error) {
+	switch cfg.Algorithm {
+	case config.TokenSigningAlgorithmRS256:
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(cfg.PrivateKey))
+		if err != nil {
+			return nil, err
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(cfg.PublicKey))
+		if err != nil {
+			return nil, err
+		}
+		return &Factory{
+			method:    jwt.SigningMethodRS256,
+			signKey:   privateKey,
+			verifyKey: publicKey,
+			issuer:    cfg.Issuer,
+		}, nil
+	case config.TokenSigningAlgorithmHS256, "":
+		if cfg.Secret == "" {
+			return nil, errors.New("token: HS256 signing requires a secret")
+		}
+		key := []byte(cfg.Secret)
+		return &Factory{
+			method:    jwt.SigningMethodHS256,
+			signKey:   key,
+			verifyKey: key,
+			issuer:    cfg.Issuer,
+		}, nil
+	default:
+		return nil, errors.New("token: unsupported signing algorithm " + string(cfg.Algorithm))
+	}
+}
+
+// Issue mints a signed access token for info.
+func (f *Factory) Issue(info skydb.AuthInfo) (string, error) {
+	var tokenValidSince int64
+	if info.TokenValidSince != nil {
+		tokenValidSince = info.TokenValidSince.Unix()
+	}
+
+	claims := Claims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:  info.ID,
+			IssuedAt: time.Now().UTC().Unix(),
+			Issuer:   f.issuer,
+		},
+		Roles:           info.Roles,
+		TokenValidSince: tokenValidSince,
+	}
+
+	return jwt.NewWithClaims(f.method, claims).SignedString(f.signKey)
+}
+
+// Verify parses and validates the signature of tokenString.
+func (f *Factory) Verify(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != f.method {
+			return nil, errors.New("token: unexpected signing method")
+		}
+		return f.verifyKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
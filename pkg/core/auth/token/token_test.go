@@ -0,0 +1,108 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skygeario/skygear-server/pkg/core/config"
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+func TestFactoryIssueVerify(t *testing.T) {
+	factory, err := NewFactory(config.TokenSigningConfiguration{
+		Algorithm: config.TokenSigningAlgorithmHS256,
+		Secret:    "a-test-secret",
+		Issuer:    "skygear-server-test",
+	})
+	if err != nil {
+		t.Fatalf("NewFactory: %v", err)
+	}
+
+	validSince := time.Now().UTC().Add(-time.Hour)
+	info := skydb.AuthInfo{
+		ID:              "user-1",
+		Roles:           []string{"admin", "support"},
+		TokenValidSince: &validSince,
+	}
+
+	tokenString, err := factory.Issue(info)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	claims, err := factory.Verify(tokenString)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	if claims.Subject != info.ID {
+		t.Errorf("claims.Subject = %q, want %q", claims.Subject, info.ID)
+	}
+	if claims.Issuer != "skygear-server-test" {
+		t.Errorf("claims.Issuer = %q, want %q", claims.Issuer, "skygear-server-test")
+	}
+	if len(claims.Roles) != 2 || claims.Roles[0] != "admin" || claims.Roles[1] != "support" {
+		t.Errorf("claims.Roles = %v, want [admin support]", claims.Roles)
+	}
+	if claims.TokenValidSince != validSince.Unix() {
+		t.Errorf("claims.TokenValidSince = %d, want %d", claims.TokenValidSince, validSince.Unix())
+	}
+}
+
+func TestFactoryVerifyRejectsDifferentSecret(t *testing.T) {
+	hs256, err := NewFactory(config.TokenSigningConfiguration{
+		Algorithm: config.TokenSigningAlgorithmHS256,
+		Secret:    "secret-one",
+	})
+	if err != nil {
+		t.Fatalf("NewFactory(hs256): %v", err)
+	}
+
+	otherHS256, err := NewFactory(config.TokenSigningConfiguration{
+		Algorithm: config.TokenSigningAlgorithmHS256,
+		Secret:    "secret-two",
+	})
+	if err != nil {
+		t.Fatalf("NewFactory(otherHS256): %v", err)
+	}
+
+	tokenString, err := hs256.Issue(skydb.AuthInfo{ID: "user-1"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	if _, err := otherHS256.Verify(tokenString); err == nil {
+		t.Error("Verify with a different secret should fail, got nil error")
+	}
+}
+
+func TestFactoryVerifyRejectsTamperedToken(t *testing.T) {
+	factory, err := NewFactory(config.TokenSigningConfiguration{
+		Algorithm: config.TokenSigningAlgorithmHS256,
+		Secret:    "a-test-secret",
+	})
+	if err != nil {
+		t.Fatalf("NewFactory: %v", err)
+	}
+
+	tokenString, err := factory.Issue(skydb.AuthInfo{ID: "user-1", Roles: []string{"member"}})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	// The signature's final base64url character carries unused padding
+	// bits, so tamper with the one before it, whose bits all count.
+	i := len(tokenString) - 2
+	replacement := "x"
+	if tokenString[i] == 'x' {
+		replacement = "y"
+	}
+	tampered := tokenString[:i] + replacement + tokenString[i+1:]
+	if tampered == tokenString {
+		t.Fatal("tampering produced an identical token string")
+	}
+
+	if _, err := factory.Verify(tampered); err == nil {
+		t.Error("Verify should reject a tampered token, got nil error")
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+
+	"github.com/skygeario/skygear-server/pkg/auth/provider/authsource"
+)
+
+type contextKeyType struct{}
+
+var contextKey = contextKeyType{}
+
+// TenantConfiguration holds the per-tenant settings that the core gears
+// need in order to serve a request: the access keys that gate the API,
+// and how this tenant's access tokens are signed.
+type TenantConfiguration struct {
+	APIKey       string                    `json:"api_key" yaml:"api_key"`
+	MasterKey    string                    `json:"master_key" yaml:"master_key"`
+	TokenSigning TokenSigningConfiguration `json:"token_signing" yaml:"token_signing"`
+	// AuthSources are the identity-provider sources this tenant has
+	// registered, addressable at /auth/login/{source.Name}.
+	AuthSources []authsource.Config `json:"auth_sources,omitempty" yaml:"auth_sources,omitempty"`
+	// AdminRoles lists the roles that RequireAdminRole accepts in place
+	// of the master key.
+	AdminRoles []string `json:"admin_roles,omitempty" yaml:"admin_roles,omitempty"`
+	// ACLDefaultEffect is the admission decision acl.Middleware applies
+	// when no server- or tenant-scope rule matches a request: "allow" or
+	// "deny". Defaults to "allow" when empty, so enabling the ACL
+	// subsystem never locks out a tenant that has not configured it yet.
+	ACLDefaultEffect string `json:"acl_default_effect,omitempty" yaml:"acl_default_effect,omitempty"`
+}
+
+// TokenSigningAlgorithm identifies the JWT signing algorithm a tenant
+// uses for minting access tokens.
+type TokenSigningAlgorithm string
+
+const (
+	// TokenSigningAlgorithmHS256 signs and verifies tokens with a single
+	// shared secret.
+	TokenSigningAlgorithmHS256 TokenSigningAlgorithm = "HS256"
+	// TokenSigningAlgorithmRS256 signs tokens with an RSA private key
+	// and verifies them with the matching public key, so that other
+	// services can validate tokens without holding the signing secret.
+	TokenSigningAlgorithmRS256 TokenSigningAlgorithm = "RS256"
+)
+
+// TokenSigningConfiguration configures how access tokens issued by this
+// tenant are signed and verified.
+type TokenSigningConfiguration struct {
+	Algorithm TokenSigningAlgorithm `json:"algorithm" yaml:"algorithm"`
+	// Secret is the shared secret used when Algorithm is HS256.
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	// PrivateKey and PublicKey are PEM-encoded RSA keys used when
+	// Algorithm is RS256.
+	PrivateKey string `json:"private_key,omitempty" yaml:"private_key,omitempty"`
+	PublicKey  string `json:"public_key,omitempty" yaml:"public_key,omitempty"`
+	Issuer     string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+}
+
+// WithTenantConfig returns a copy of ctx carrying c, so that later
+// middleware and policies can recover the tenant configuration for the
+// request being served.
+func WithTenantConfig(ctx context.Context, c TenantConfiguration) context.Context {
+	return context.WithValue(ctx, contextKey, c)
+}
+
+// TenantConfigFromContext returns the TenantConfiguration attached to
+// ctx, if any.
+func TenantConfigFromContext(ctx context.Context) (TenantConfiguration, bool) {
+	c, ok := ctx.Value(contextKey).(TenantConfiguration)
+	return c, ok
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// dialectPlaceholders implements squirrel's PlaceholderFormat by
+// rewriting each "?" squirrel emits into dialect's positional
+// placeholder, so a single SQLBuilder works unchanged across Postgres,
+// MySQL, and SQLite.
+type dialectPlaceholders struct {
+	dialect Dialect
+}
+
+func (p dialectPlaceholders) ReplacePlaceholders(sql string) (string, error) {
+	var b strings.Builder
+	n := 0
+	for _, r := range sql {
+		if r == '?' {
+			n++
+			b.WriteString(p.dialect.Placeholder(n))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
+
+// sqlBuilder is the dialect-aware SQLBuilder backed by squirrel.
+type sqlBuilder struct {
+	schema  string
+	dialect Dialect
+}
+
+// NewSQLBuilder returns a SQLBuilder that binds parameters using
+// dialect's placeholder style and, if schema is non-empty, qualifies
+// every table name under it (e.g. a tenant's own Postgres schema).
+func NewSQLBuilder(dialect Dialect, schema string) SQLBuilder {
+	return &sqlBuilder{schema: schema, dialect: dialect}
+}
+
+func (b *sqlBuilder) placeholderFormat() sq.PlaceholderFormat {
+	return dialectPlaceholders{dialect: b.dialect}
+}
+
+func (b *sqlBuilder) TableName(name string) string {
+	if b.schema == "" {
+		return name
+	}
+	return b.schema + "." + name
+}
+
+func (b *sqlBuilder) Insert(into string) sq.InsertBuilder {
+	return sq.Insert(into).PlaceholderFormat(b.placeholderFormat())
+}
+
+func (b *sqlBuilder) Select(columns ...string) sq.SelectBuilder {
+	return sq.Select(columns...).PlaceholderFormat(b.placeholderFormat())
+}
+
+func (b *sqlBuilder) Update(table string) sq.UpdateBuilder {
+	return sq.Update(table).PlaceholderFormat(b.placeholderFormat())
+}
+
+func (b *sqlBuilder) Delete(from string) sq.DeleteBuilder {
+	return sq.Delete(from).PlaceholderFormat(b.placeholderFormat())
+}
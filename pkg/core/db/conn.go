@@ -0,0 +1,82 @@
+package db
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// querier is the subset of *sql.DB and *sql.Tx that Conn needs to run
+// the SQL a SQLBuilder produces.
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Conn is a TxContext and SQLExecutor backed by a single *sql.DB, for
+// deployments (and the SQLite test harness) that talk to one connection
+// rather than a per-tenant pool. Outside of WithTx, ExecWith/QueryWith/
+// QueryRowWith run directly against the underlying *sql.DB.
+type Conn struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+// NewConn wraps conn as a Conn.
+func NewConn(conn *sql.DB) *Conn {
+	return &Conn{db: conn}
+}
+
+func (c *Conn) querier() querier {
+	if c.tx != nil {
+		return c.tx
+	}
+	return c.db
+}
+
+// WithTx runs fn inside a *sql.Tx, committing on success and rolling
+// back if fn returns an error or panics.
+func (c *Conn) WithTx(fn func() error) (err error) {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	c.tx = tx
+	defer func() { c.tx = nil }()
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	err = fn()
+	return err
+}
+
+func (c *Conn) ExecWith(sqlizer sq.Sqlizer) (sql.Result, error) {
+	query, args, err := sqlizer.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return c.querier().Exec(query, args...)
+}
+
+func (c *Conn) QueryWith(sqlizer sq.Sqlizer) (*sql.Rows, error) {
+	query, args, err := sqlizer.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return c.querier().Query(query, args...)
+}
+
+func (c *Conn) QueryRowWith(sqlizer sq.Sqlizer) *sql.Row {
+	query, args, _ := sqlizer.ToSql()
+	return c.querier().QueryRow(query, args...)
+}
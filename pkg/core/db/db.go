@@ -0,0 +1,8 @@
+package db
+
+// TxContext manages a database transaction spanning a single request.
+// Handlers that opt into WithTx run their Handle method through WithTx
+// so that a failure anywhere rolls back every write made along the way.
+type TxContext interface {
+	WithTx(fn func() error) error
+}
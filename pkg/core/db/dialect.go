@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+)
+
+// Dialect isolates the handful of places SQL differs across the
+// database engines a tenant's SQLBuilder/SQLExecutor pair might be
+// backed by, so record.Store, role.Store, and the other core stores can
+// compile against Postgres, MySQL, or SQLite alike.
+type Dialect interface {
+	// Name identifies the dialect, and the <Name()>/ directory of the
+	// migrations FS that RunMigrations loads from.
+	Name() string
+
+	// Placeholder returns the positional parameter placeholder for the
+	// n-th (1-indexed) bound argument, e.g. "$1" for Postgres, "?" for
+	// MySQL and SQLite.
+	Placeholder(n int) string
+
+	// QuoteIdent quotes name as a safe identifier for this dialect.
+	QuoteIdent(name string) string
+
+	// JSONExtract returns an expression extracting path from the JSON
+	// document stored in column.
+	JSONExtract(column string, path string) string
+
+	// UpsertClause returns the trailing clause an INSERT needs appended
+	// so it updates updateColumns instead of failing when a row
+	// conflicting on conflictColumns already exists.
+	UpsertClause(conflictColumns []string, updateColumns []string) string
+
+	// Migrate applies every migration in the <Name()>/ directory of
+	// migrations to conn, in lexical filename order.
+	Migrate(ctx context.Context, conn *sql.DB, migrations fs.FS) error
+}
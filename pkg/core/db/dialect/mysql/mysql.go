@@ -0,0 +1,44 @@
+// Package mysql is the MySQL db.Dialect.
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/skygeario/skygear-server/pkg/core/db"
+)
+
+type dialect struct{}
+
+// Dialect is the MySQL db.Dialect.
+var Dialect db.Dialect = dialect{}
+
+func (dialect) Name() string { return "mysql" }
+
+func (dialect) Placeholder(n int) string { return "?" }
+
+func (dialect) QuoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (dialect) JSONExtract(column string, path string) string {
+	return fmt.Sprintf("JSON_EXTRACT(%s, '$.%s')", column, path)
+}
+
+// UpsertClause ignores conflictColumns: MySQL's ON DUPLICATE KEY UPDATE
+// infers the conflicting key from the table's own primary/unique key
+// rather than naming it in the clause.
+func (dialect) UpsertClause(conflictColumns []string, updateColumns []string) string {
+	set := make([]string, len(updateColumns))
+	for i, col := range updateColumns {
+		set[i] = fmt.Sprintf("%s = VALUES(%s)", col, col)
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(set, ", ")
+}
+
+func (dialect) Migrate(ctx context.Context, conn *sql.DB, migrations fs.FS) error {
+	return db.RunMigrations(ctx, conn, migrations, "mysql")
+}
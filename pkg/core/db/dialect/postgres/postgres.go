@@ -0,0 +1,41 @@
+// Package postgres is the production-default db.Dialect.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/skygeario/skygear-server/pkg/core/db"
+)
+
+type dialect struct{}
+
+// Dialect is the Postgres db.Dialect.
+var Dialect db.Dialect = dialect{}
+
+func (dialect) Name() string { return "postgres" }
+
+func (dialect) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func (dialect) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (dialect) JSONExtract(column string, path string) string {
+	return fmt.Sprintf("%s #>> '{%s}'", column, path)
+}
+
+func (dialect) UpsertClause(conflictColumns []string, updateColumns []string) string {
+	set := make([]string, len(updateColumns))
+	for i, col := range updateColumns {
+		set[i] = fmt.Sprintf("%s = EXCLUDED.%s", col, col)
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(conflictColumns, ", "), strings.Join(set, ", "))
+}
+
+func (dialect) Migrate(ctx context.Context, conn *sql.DB, migrations fs.FS) error {
+	return db.RunMigrations(ctx, conn, migrations, "postgres")
+}
@@ -0,0 +1,65 @@
+//go:build cgo
+// +build cgo
+
+// Package sqlite is the CGO-backed db.Dialect used by the in-process
+// test harness (OpenMemory) so the auth and record test suites can run
+// without a real Postgres.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/skygeario/skygear-server/pkg/core/db"
+)
+
+type dialect struct{}
+
+// Dialect is the SQLite db.Dialect.
+var Dialect db.Dialect = dialect{}
+
+func (dialect) Name() string { return "sqlite" }
+
+func (dialect) Placeholder(n int) string { return "?" }
+
+func (dialect) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (dialect) JSONExtract(column string, path string) string {
+	return fmt.Sprintf("json_extract(%s, '$.%s')", column, path)
+}
+
+func (dialect) UpsertClause(conflictColumns []string, updateColumns []string) string {
+	set := make([]string, len(updateColumns))
+	for i, col := range updateColumns {
+		set[i] = fmt.Sprintf("%s = excluded.%s", col, col)
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(conflictColumns, ", "), strings.Join(set, ", "))
+}
+
+func (dialect) Migrate(ctx context.Context, conn *sql.DB, migrations fs.FS) error {
+	return db.RunMigrations(ctx, conn, migrations, "sqlite")
+}
+
+// OpenMemory opens a fresh in-process, in-memory SQLite database and
+// applies migrations against it, for a test harness that wants a real
+// SQL engine without spinning up Postgres.
+func OpenMemory(ctx context.Context, migrations fs.FS) (*sql.DB, error) {
+	conn, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Dialect.Migrate(ctx, conn, migrations); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// RunMigrations applies every *.sql file in the <dialectName>/ directory
+// of migrations to conn, in lexical filename order, executing each
+// file's whole contents in a single ExecContext call. Every
+// Dialect.Migrate implementation delegates here so the directory-walking
+// logic lives in exactly one place.
+func RunMigrations(ctx context.Context, conn *sql.DB, migrations fs.FS, dialectName string) error {
+	dir := dialectName
+	entries, err := fs.ReadDir(migrations, dir)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		contents, err := fs.ReadFile(migrations, dir+"/"+name)
+		if err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, string(contents)); err != nil {
+			return fmt.Errorf("db: migration %s failed: %w", name, err)
+		}
+	}
+	return nil
+}
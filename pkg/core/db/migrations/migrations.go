@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL files that create the core gears'
+// shared tables (_role, _role_binding, _auth_source, _audit_log,
+// _field_access, _acl_rule), one directory per db.Dialect, for
+// Dialect.Migrate to apply.
+package migrations
+
+import "embed"
+
+//go:embed postgres/*.sql mysql/*.sql sqlite/*.sql
+var FS embed.FS
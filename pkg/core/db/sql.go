@@ -0,0 +1,25 @@
+package db
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// SQLBuilder builds dialect-aware, tenant-schema-scoped SQL.
+type SQLBuilder interface {
+	// TableName returns name qualified for the current tenant's schema.
+	TableName(name string) string
+	Insert(into string) sq.InsertBuilder
+	Select(columns ...string) sq.SelectBuilder
+	Update(table string) sq.UpdateBuilder
+	Delete(from string) sq.DeleteBuilder
+}
+
+// SQLExecutor runs SQL built by a SQLBuilder against the current
+// request's transaction.
+type SQLExecutor interface {
+	ExecWith(sqlizer sq.Sqlizer) (sql.Result, error)
+	QueryWith(sqlizer sq.Sqlizer) (*sql.Rows, error)
+	QueryRowWith(sqlizer sq.Sqlizer) *sql.Row
+}
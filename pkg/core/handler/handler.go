@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/skygeario/skygear-server/pkg/core/db"
+)
+
+// RequestPayload is implemented by a decoded request body so it can
+// validate itself before an APIHandler's Handle method runs.
+type RequestPayload interface {
+	Validate() error
+}
+
+// APIHandler is the decode/validate/handle convention used by handlers
+// across the core gears.
+type APIHandler interface {
+	DecodeRequest(request *http.Request) (RequestPayload, error)
+	Handle(payload interface{}) (response interface{}, err error)
+}
+
+// TxAPIHandler is an APIHandler that opts in to running Handle inside a
+// database transaction.
+type TxAPIHandler interface {
+	APIHandler
+	WithTx() bool
+}
+
+// APIHandlerToHandler adapts h to a standard http.Handler: it decodes
+// and validates the request payload, runs Handle (inside a transaction
+// via txContext when h opts in via WithTx), and writes the result as a
+// JSON response or error envelope.
+func APIHandlerToHandler(h APIHandler, txContext db.TxContext) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := h.DecodeRequest(r)
+		if err == nil {
+			err = payload.Validate()
+		}
+
+		var resp interface{}
+		if err == nil {
+			if tx, ok := h.(TxAPIHandler); ok && tx.WithTx() && txContext != nil {
+				err = txContext.WithTx(func() error {
+					var txErr error
+					resp, txErr = h.Handle(payload)
+					return txErr
+				})
+			} else {
+				resp, err = h.Handle(payload)
+			}
+		}
+
+		writeResponse(w, resp, err)
+	})
+}
+
+func writeResponse(w http.ResponseWriter, resp interface{}, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,35 @@
+package inject
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// DependencyMap resolves a named dependency for the given request. Each
+// gear (auth, record, ...) implements one to wire its handlers' concrete
+// stores, loggers, and other collaborators together.
+type DependencyMap interface {
+	Provide(name string, request *http.Request) interface{}
+}
+
+// DefaultInject populates every field of h tagged `dependency:"Name"`
+// with the dependency m.Provide resolves for that name, using request to
+// scope tenant- and request-bound dependencies such as DB connections.
+func DefaultInject(h interface{}, m DependencyMap, request *http.Request) {
+	hv := reflect.ValueOf(h).Elem()
+	ht := hv.Type()
+
+	for i := 0; i < ht.NumField(); i++ {
+		name, ok := ht.Field(i).Tag.Lookup("dependency")
+		if !ok {
+			continue
+		}
+
+		dep := m.Provide(name, request)
+		if dep == nil {
+			continue
+		}
+
+		hv.Field(i).Set(reflect.ValueOf(dep))
+	}
+}
@@ -0,0 +1,149 @@
+// Package acl implements the server- and tenant-scoped Allow/Deny rule
+// engine that Middleware runs ahead of handler dispatch.
+package acl
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+// Scope distinguishes server-wide rules, loaded once from a config file
+// at startup, from tenant-scoped rules managed through /admin/acl.
+type Scope string
+
+const (
+	ScopeServer Scope = "server"
+	ScopeTenant Scope = "tenant"
+)
+
+// Effect is the admission decision a matching Rule carries out.
+type Effect string
+
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+// HeaderMatch matches a request header by name against a regular
+// expression.
+type HeaderMatch struct {
+	Name  string `json:"name,omitempty"`
+	Regex string `json:"regex,omitempty"`
+}
+
+// Match describes the request shape a Rule applies to. A zero-valued
+// field is a wildcard that matches any request.
+type Match struct {
+	RemoteCIDR  string      `json:"remote_cidr,omitempty"`
+	Header      HeaderMatch `json:"header,omitempty"`
+	Path        string      `json:"path,omitempty"`
+	Method      string      `json:"method,omitempty"`
+	PrincipalID string      `json:"principal_id,omitempty"`
+	Role        string      `json:"role,omitempty"`
+}
+
+// Rule is one ordered Allow/Deny rule. Rules are evaluated first-match-
+// wins, with server-scope rules (config file) evaluated ahead of
+// tenant-scope rules (admin-managed).
+type Rule struct {
+	ID     string `json:"id"`
+	Scope  Scope  `json:"scope"`
+	Effect Effect `json:"effect"`
+	Match  Match  `json:"match"`
+}
+
+// Validate checks that r is well-formed.
+func (r Rule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("acl: id is required")
+	}
+	switch r.Scope {
+	case ScopeServer, ScopeTenant:
+	default:
+		return fmt.Errorf("acl: unsupported scope %q", r.Scope)
+	}
+	switch r.Effect {
+	case EffectAllow, EffectDeny:
+	default:
+		return fmt.Errorf("acl: unsupported effect %q", r.Effect)
+	}
+	if r.Match.Header.Name != "" {
+		if _, err := regexp.Compile(r.Match.Header.Regex); err != nil {
+			return fmt.Errorf("acl: invalid header regex: %w", err)
+		}
+	}
+	if r.Match.RemoteCIDR != "" {
+		if _, _, err := net.ParseCIDR(r.Match.RemoteCIDR); err != nil {
+			return fmt.Errorf("acl: invalid remote_cidr: %w", err)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether r's Match criteria are all satisfied by
+// request, for the given authInfo (nil if the request is unauthenticated).
+func (r Rule) Matches(request *http.Request, authInfo *skydb.AuthInfo) bool {
+	m := r.Match
+
+	if m.RemoteCIDR != "" && !remoteMatchesCIDR(request, m.RemoteCIDR) {
+		return false
+	}
+	if m.Header.Name != "" {
+		re, err := regexp.Compile(m.Header.Regex)
+		if err != nil || !re.MatchString(request.Header.Get(m.Header.Name)) {
+			return false
+		}
+	}
+	if m.Path != "" && !pathMatches(request.URL.Path, m.Path) {
+		return false
+	}
+	if m.Method != "" && !strings.EqualFold(request.Method, m.Method) {
+		return false
+	}
+	if m.PrincipalID != "" && (authInfo == nil || authInfo.ID != m.PrincipalID) {
+		return false
+	}
+	if m.Role != "" && (authInfo == nil || !authInfo.HasAnyRoles([]string{m.Role})) {
+		return false
+	}
+	return true
+}
+
+func remoteMatchesCIDR(request *http.Request, cidr string) bool {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && network.Contains(ip)
+}
+
+// pathMatches treats a trailing "*" in pattern as a prefix wildcard,
+// otherwise requiring an exact match.
+func pathMatches(path string, pattern string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(path, strings.TrimSuffix(pattern, "*"))
+	}
+	return path == pattern
+}
+
+// Evaluate walks rules in order and returns the Effect of the first
+// Rule matching request/authInfo, or defaultEffect (with a nil Rule) if
+// none match.
+func Evaluate(rules []Rule, request *http.Request, authInfo *skydb.AuthInfo, defaultEffect Effect) (Effect, *Rule) {
+	for i := range rules {
+		if rules[i].Matches(request, authInfo) {
+			return rules[i].Effect, &rules[i]
+		}
+	}
+	return defaultEffect, nil
+}
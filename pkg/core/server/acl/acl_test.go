@@ -0,0 +1,104 @@
+package acl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+func newRequest(remoteAddr, method, path string) *http.Request {
+	r := httptest.NewRequest(method, path, nil)
+	r.RemoteAddr = remoteAddr
+	return r
+}
+
+func TestRuleMatches(t *testing.T) {
+	admin := &skydb.AuthInfo{ID: "user-1", Roles: []string{"admin"}}
+
+	tests := []struct {
+		name     string
+		match    Match
+		request  *http.Request
+		authInfo *skydb.AuthInfo
+		want     bool
+	}{
+		{"zero value matches anything", Match{}, newRequest("10.0.0.1:1234", "GET", "/x"), nil, true},
+		{"remote_cidr matches an in-range address", Match{RemoteCIDR: "10.0.0.0/8"}, newRequest("10.1.2.3:1234", "GET", "/x"), nil, true},
+		{"remote_cidr rejects an out-of-range address", Match{RemoteCIDR: "10.0.0.0/8"}, newRequest("192.168.1.1:1234", "GET", "/x"), nil, false},
+		{"path exact match", Match{Path: "/admin/roles"}, newRequest("10.0.0.1:1", "GET", "/admin/roles"), nil, true},
+		{"path exact match rejects a different path", Match{Path: "/admin/roles"}, newRequest("10.0.0.1:1", "GET", "/admin/other"), nil, false},
+		{"path wildcard matches a prefix", Match{Path: "/admin/*"}, newRequest("10.0.0.1:1", "GET", "/admin/roles/x"), nil, true},
+		{"method matches case-insensitively", Match{Method: "post"}, newRequest("10.0.0.1:1", "POST", "/x"), nil, true},
+		{"method rejects a different method", Match{Method: "POST"}, newRequest("10.0.0.1:1", "GET", "/x"), nil, false},
+		{"principal_id rejects an unauthenticated request", Match{PrincipalID: "user-1"}, newRequest("10.0.0.1:1", "GET", "/x"), nil, false},
+		{"principal_id matches the named principal", Match{PrincipalID: "user-1"}, newRequest("10.0.0.1:1", "GET", "/x"), admin, true},
+		{"role rejects a principal without it", Match{Role: "admin"}, newRequest("10.0.0.1:1", "GET", "/x"), &skydb.AuthInfo{ID: "user-2"}, false},
+		{"role matches a principal with it", Match{Role: "admin"}, newRequest("10.0.0.1:1", "GET", "/x"), admin, true},
+		{"header matches its regex", Match{Header: HeaderMatch{Name: "X-Auth-Request-User", Regex: "^admin$"}}, headerRequest("X-Auth-Request-User", "admin"), nil, true},
+		{"header rejects a non-matching value", Match{Header: HeaderMatch{Name: "X-Auth-Request-User", Regex: "^admin$"}}, headerRequest("X-Auth-Request-User", "someone-else"), nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Rule{ID: "r1", Scope: ScopeServer, Effect: EffectAllow, Match: tt.match}
+			if got := r.Matches(tt.request, tt.authInfo); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func headerRequest(name, value string) *http.Request {
+	r := newRequest("10.0.0.1:1234", "GET", "/x")
+	r.Header.Set(name, value)
+	return r
+}
+
+func TestEvaluateFirstMatchWins(t *testing.T) {
+	rules := []Rule{
+		{ID: "deny-bad-actor", Scope: ScopeTenant, Effect: EffectDeny, Match: Match{PrincipalID: "bad-actor"}},
+		{ID: "allow-admin", Scope: ScopeTenant, Effect: EffectAllow, Match: Match{Role: "admin"}},
+	}
+
+	effect, matched := Evaluate(rules, newRequest("10.0.0.1:1", "GET", "/x"), &skydb.AuthInfo{ID: "bad-actor", Roles: []string{"admin"}}, EffectAllow)
+	if effect != EffectDeny || matched == nil || matched.ID != "deny-bad-actor" {
+		t.Errorf("Evaluate() = (%v, %v), want (deny, deny-bad-actor) since the deny rule is listed first", effect, matched)
+	}
+}
+
+func TestEvaluateFallsBackToDefaultEffect(t *testing.T) {
+	rules := []Rule{
+		{ID: "allow-admin", Scope: ScopeTenant, Effect: EffectAllow, Match: Match{Role: "admin"}},
+	}
+
+	effect, matched := Evaluate(rules, newRequest("10.0.0.1:1", "GET", "/x"), &skydb.AuthInfo{ID: "user-1"}, EffectDeny)
+	if effect != EffectDeny || matched != nil {
+		t.Errorf("Evaluate() = (%v, %v), want (deny, nil) when no rule matches", effect, matched)
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{"valid rule", Rule{ID: "r1", Scope: ScopeServer, Effect: EffectAllow}, false},
+		{"missing id", Rule{Scope: ScopeServer, Effect: EffectAllow}, true},
+		{"invalid scope", Rule{ID: "r1", Scope: "bogus", Effect: EffectAllow}, true},
+		{"invalid effect", Rule{ID: "r1", Scope: ScopeServer, Effect: "bogus"}, true},
+		{"invalid header regex", Rule{ID: "r1", Scope: ScopeServer, Effect: EffectAllow, Match: Match{Header: HeaderMatch{Name: "X-Foo", Regex: "("}}}, true},
+		{"invalid remote_cidr", Rule{ID: "r1", Scope: ScopeServer, Effect: EffectAllow, Match: Match{RemoteCIDR: "not-a-cidr"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
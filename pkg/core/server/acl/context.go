@@ -0,0 +1,21 @@
+package acl
+
+import "context"
+
+type contextKeyType struct{}
+
+var contextKey = contextKeyType{}
+
+// WithRequestID returns a copy of ctx carrying requestID, so the
+// server's access log line can correlate with a denial this middleware
+// logged.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKey, requestID)
+}
+
+// RequestIDFromContext returns the request id Middleware generated for
+// the current request, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(contextKey).(string)
+	return requestID, ok
+}
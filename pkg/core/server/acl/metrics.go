@@ -0,0 +1,24 @@
+package acl
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	allowTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "acl_allow_total",
+			Help: "Total requests admitted by the ACL middleware, labeled by the scope and id of the rule that allowed them (\"default\"/\"default\" when no rule matched).",
+		},
+		[]string{"scope", "rule_id"},
+	)
+	denyTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "acl_deny_total",
+			Help: "Total requests rejected by the ACL middleware, labeled by the scope and id of the rule that denied them (\"default\"/\"default\" when no rule matched).",
+		},
+		[]string{"scope", "rule_id"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(allowTotal, denyTotal)
+}
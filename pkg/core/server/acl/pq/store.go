@@ -0,0 +1,98 @@
+package pq
+
+import (
+	"database/sql"
+
+	"github.com/skygeario/skygear-server/pkg/core/db"
+	"github.com/skygeario/skygear-server/pkg/core/server/acl"
+)
+
+type store struct {
+	sqlBuilder  db.SQLBuilder
+	sqlExecutor db.SQLExecutor
+}
+
+// NewStore returns an acl.Store backed by sqlBuilder/sqlExecutor. It
+// only ever deals in tenant-scope rules: server-scope rules are loaded
+// from a config file, not this table.
+func NewStore(sqlBuilder db.SQLBuilder, sqlExecutor db.SQLExecutor) acl.Store {
+	return &store{sqlBuilder: sqlBuilder, sqlExecutor: sqlExecutor}
+}
+
+func (s *store) List() ([]acl.Rule, error) {
+	rows, err := s.sqlExecutor.QueryWith(
+		s.sqlBuilder.Select(
+			"id", "effect", "remote_cidr", "header_name", "header_regex",
+			"path", "method", "principal_id", "role",
+		).From(s.sqlBuilder.TableName("_acl_rule")).OrderBy("id"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []acl.Rule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (s *store) Create(rule acl.Rule) error {
+	_, err := s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Insert(s.sqlBuilder.TableName("_acl_rule")).
+			Columns(
+				"id", "effect", "remote_cidr", "header_name", "header_regex",
+				"path", "method", "principal_id", "role",
+			).
+			Values(
+				rule.ID, string(rule.Effect), rule.Match.RemoteCIDR, rule.Match.Header.Name, rule.Match.Header.Regex,
+				rule.Match.Path, rule.Match.Method, rule.Match.PrincipalID, rule.Match.Role,
+			),
+	)
+	return err
+}
+
+func (s *store) Delete(id string) error {
+	result, err := s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Delete(s.sqlBuilder.TableName("_acl_rule")).Where("id = ?", id),
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return acl.ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRule(row rowScanner) (acl.Rule, error) {
+	rule := acl.Rule{Scope: acl.ScopeTenant}
+	var effect string
+
+	if err := row.Scan(
+		&rule.ID, &effect, &rule.Match.RemoteCIDR, &rule.Match.Header.Name, &rule.Match.Header.Regex,
+		&rule.Match.Path, &rule.Match.Method, &rule.Match.PrincipalID, &rule.Match.Role,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return acl.Rule{}, acl.ErrNotFound
+		}
+		return acl.Rule{}, err
+	}
+
+	rule.Effect = acl.Effect(effect)
+	return rule, nil
+}
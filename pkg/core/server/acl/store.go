@@ -0,0 +1,15 @@
+package acl
+
+import "errors"
+
+// ErrNotFound is returned when a named tenant-scope rule does not exist.
+var ErrNotFound = errors.New("acl: not found")
+
+// Store persists a tenant's ACL rules, managed through /admin/acl.
+// Server-scope rules never live here; they are loaded from a config
+// file at startup. List returns rules in evaluation order.
+type Store interface {
+	List() ([]Rule, error)
+	Create(rule Rule) error
+	Delete(id string) error
+}
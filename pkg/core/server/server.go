@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+)
+
+// HandlerFactory constructs a per-request http.Handler, resolving its
+// dependencies against the request being served.
+type HandlerFactory interface {
+	NewHandler(request *http.Request) http.Handler
+}
+
+// AuthzPolicyProvider is implemented by a HandlerFactory that gates its
+// handler behind an authz.Policy. Server checks it before calling
+// NewHandler.
+type AuthzPolicyProvider interface {
+	ProvideAuthzPolicy() authz.Policy
+}
+
+// Server routes HTTP requests to gear handlers, enforcing each
+// handler's authz.Policy before it is constructed.
+type Server struct {
+	router *mux.Router
+}
+
+// NewServer returns an empty Server ready to have gears attach their
+// handlers to it.
+func NewServer() *Server {
+	return &Server{router: mux.NewRouter()}
+}
+
+// Handle registers factory to serve path.
+func (s *Server) Handle(path string, factory HandlerFactory) *mux.Route {
+	return s.router.Handle(path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if provider, ok := factory.(AuthzPolicyProvider); ok {
+			if err := provider.ProvideAuthzPolicy().IsAllowed(r); err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		factory.NewHandler(r).ServeHTTP(w, r)
+	}))
+}
+
+// Use appends middleware to the chain every request passes through
+// before reaching Handle's routing.
+func (s *Server) Use(middleware func(http.Handler) http.Handler) {
+	s.router.Use(mux.MiddlewareFunc(middleware))
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
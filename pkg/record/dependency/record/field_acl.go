@@ -0,0 +1,167 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+// ownerIDField is the conventional column holding a record's owner,
+// mirroring the "_owner_id" metadata key used elsewhere in skydb.
+const ownerIDField = "_owner_id"
+
+// ErrFieldNotComparable is returned when a query predicate references a
+// field the requesting AuthInfo is not allowed to compare against.
+var ErrFieldNotComparable = errors.New("record: field is not comparable")
+
+// FieldACL is a tenant's full Field ACL ruleset, indexed by record type
+// for fast lookup at query time. The zero value is an empty ruleset
+// under which every field defaults to fully accessible.
+type FieldACL struct {
+	entriesByRecordType map[string][]FieldACLEntry
+}
+
+// NewFieldACL builds a FieldACL from its persisted entries.
+func NewFieldACL(entries []FieldACLEntry) FieldACL {
+	acl := FieldACL{entriesByRecordType: map[string][]FieldACLEntry{}}
+	for _, e := range entries {
+		acl.entriesByRecordType[e.RecordType] = append(acl.entriesByRecordType[e.RecordType], e)
+	}
+	return acl
+}
+
+// Entries returns every entry in the ruleset, e.g. to persist it.
+func (acl FieldACL) Entries() []FieldACLEntry {
+	entries := []FieldACLEntry{}
+	for _, es := range acl.entriesByRecordType {
+		entries = append(entries, es...)
+	}
+	return entries
+}
+
+// fieldAccess is the union of permissions granted by every entry
+// matching a given (recordType, field, authInfo, row) combination.
+type fieldAccess struct {
+	Readable     bool
+	Writable     bool
+	Comparable   bool
+	Discoverable bool
+}
+
+// fullAccess is what a field defaults to when no entry governs it:
+// Field ACL only restricts what it is explicitly told to.
+var fullAccess = fieldAccess{Readable: true, Writable: true, Comparable: true, Discoverable: true}
+
+func (acl FieldACL) fieldAccess(authInfo *skydb.AuthInfo, recordType string, field string, row skydb.Data) fieldAccess {
+	var access fieldAccess
+	governed := false
+
+	for _, e := range acl.entriesByRecordType[recordType] {
+		if e.RecordField != field {
+			continue
+		}
+		governed = true
+		if !e.matches(authInfo, row) {
+			continue
+		}
+		access.Readable = access.Readable || e.Readable
+		access.Writable = access.Writable || e.Writable
+		access.Comparable = access.Comparable || e.Comparable
+		access.Discoverable = access.Discoverable || e.Discoverable
+	}
+
+	if !governed {
+		return fullAccess
+	}
+	return access
+}
+
+// matches reports whether e's UserRole applies to authInfo given row,
+// the full set of stored columns for the record being evaluated. row
+// may be nil when evaluating a predicate field in isolation (_owner and
+// _field rules then never match, since they need row data).
+func (e FieldACLEntry) matches(authInfo *skydb.AuthInfo, row skydb.Data) bool {
+	switch {
+	case e.UserRole == userRolePublic:
+		return true
+	case e.UserRole == userRoleAnyUser:
+		return authInfo != nil
+	case e.UserRole == userRoleOwner:
+		return authInfo != nil && row != nil && row[ownerIDField] == authInfo.ID
+	case strings.HasPrefix(e.UserRole, userRoleUserIDPrefix):
+		return authInfo != nil && authInfo.ID == strings.TrimPrefix(e.UserRole, userRoleUserIDPrefix)
+	case strings.HasPrefix(e.UserRole, userRoleRolePrefix):
+		return authInfo != nil && authInfo.HasAnyRoles([]string{strings.TrimPrefix(e.UserRole, userRoleRolePrefix)})
+	case strings.HasPrefix(e.UserRole, userRoleFieldPrefix):
+		if authInfo == nil || row == nil {
+			return false
+		}
+		field := strings.TrimPrefix(e.UserRole, userRoleFieldPrefix)
+		value, _ := row[field].(string)
+		return value == authInfo.ID
+	default:
+		return false
+	}
+}
+
+// CheckComparable returns ErrFieldNotComparable if predicate references
+// any field that authInfo may not compare against under acl.
+func (acl FieldACL) CheckComparable(authInfo *skydb.AuthInfo, recordType string, predicate skydb.Predicate) error {
+	for _, field := range predicateFields(predicate) {
+		if !acl.fieldAccess(authInfo, recordType, field, nil).Comparable {
+			return fmt.Errorf("%w: %s.%s", ErrFieldNotComparable, recordType, field)
+		}
+	}
+	return nil
+}
+
+// Project returns a copy of row containing only the fields authInfo may
+// read. desiredKeys lists the fields the query asked for; a single
+// WildcardKey entry asks for every field, in which case non-discoverable
+// fields are left out as well. With an explicit list, unreadable fields
+// are left out and discoverability is not checked. No error is returned
+// for an omitted field in either case.
+func (acl FieldACL) Project(authInfo *skydb.AuthInfo, recordType string, row skydb.Data, desiredKeys []string) skydb.Data {
+	projected := skydb.Data{}
+
+	wildcard := len(desiredKeys) == 1 && desiredKeys[0] == WildcardKey
+	keys := desiredKeys
+	if wildcard {
+		keys = make([]string, 0, len(row))
+		for k := range row {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, field := range keys {
+		access := acl.fieldAccess(authInfo, recordType, field, row)
+		if wildcard && !access.Discoverable {
+			continue
+		}
+		if !access.Readable {
+			continue
+		}
+		projected[field] = row[field]
+	}
+
+	return projected
+}
+
+func predicateFields(p skydb.Predicate) []string {
+	fields := []string{}
+	for _, child := range p.Children {
+		switch c := child.(type) {
+		case skydb.Predicate:
+			fields = append(fields, predicateFields(c)...)
+		case skydb.Expression:
+			if c.Type == skydb.KeyPath {
+				if field, ok := c.Value.(string); ok {
+					fields = append(fields, field)
+				}
+			}
+		}
+	}
+	return fields
+}
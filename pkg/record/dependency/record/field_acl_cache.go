@@ -0,0 +1,55 @@
+package record
+
+import "sync"
+
+// FieldACLCache caches a tenant's FieldACL so hot query paths don't hit
+// the database on every request. It is safe for concurrent use and is
+// expected to live as long as the tenant it caches for, outliving any
+// single recordStore.
+type FieldACLCache struct {
+	mu         sync.RWMutex
+	generation uint64
+	cached     *FieldACL
+	cachedGen  uint64
+}
+
+// Get returns the cached FieldACL if it is still current, otherwise
+// loading and caching the result of load.
+func (c *FieldACLCache) Get(load func() (FieldACL, error)) (FieldACL, error) {
+	c.mu.RLock()
+	if c.cached != nil && c.cachedGen == c.generation {
+		acl := *c.cached
+		c.mu.RUnlock()
+		return acl, nil
+	}
+	gen := c.generation
+	c.mu.RUnlock()
+
+	acl, err := load()
+	if err != nil {
+		return FieldACL{}, err
+	}
+
+	// gen is the generation this load started against, captured before
+	// the (possibly slow) load ran. If a concurrent Invalidate bumped
+	// c.generation while load was in flight, acl may already reflect a
+	// ruleset older than the one that invalidation was for, so it must
+	// not be cached — otherwise the invalidation's effect could be
+	// silently masked by this stale value committing after it.
+	c.mu.Lock()
+	if c.generation == gen {
+		c.cached = &acl
+		c.cachedGen = gen
+	}
+	c.mu.Unlock()
+
+	return acl, nil
+}
+
+// Invalidate bumps the cache generation so the next Get reloads from
+// load, for use after a write to the persisted ruleset.
+func (c *FieldACLCache) Invalidate() {
+	c.mu.Lock()
+	c.generation++
+	c.mu.Unlock()
+}
@@ -0,0 +1,76 @@
+package record
+
+import "testing"
+
+func TestFieldACLCacheGetCachesLoadResult(t *testing.T) {
+	c := &FieldACLCache{}
+	calls := 0
+	load := func() (FieldACL, error) {
+		calls++
+		return NewFieldACL([]FieldACLEntry{{RecordType: "note"}}), nil
+	}
+
+	if _, err := c.Get(load); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := c.Get(load); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("load called %d times, want 1 (second Get should hit the cache)", calls)
+	}
+}
+
+func TestFieldACLCacheGetReloadsAfterInvalidate(t *testing.T) {
+	c := &FieldACLCache{}
+	calls := 0
+	load := func() (FieldACL, error) {
+		calls++
+		return NewFieldACL([]FieldACLEntry{{RecordType: "note"}}), nil
+	}
+
+	if _, err := c.Get(load); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	c.Invalidate()
+	if _, err := c.Get(load); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("load called %d times, want 2 (Get after Invalidate should reload)", calls)
+	}
+}
+
+// TestFieldACLCacheGetDoesNotCacheStaleLoadAcrossInvalidate reproduces a
+// concurrent Invalidate landing while a Get's load is in flight: the
+// in-flight load started against the pre-invalidate generation, so its
+// result must not be cached over the invalidation, or the next Get would
+// wrongly serve the stale value instead of reloading.
+func TestFieldACLCacheGetDoesNotCacheStaleLoadAcrossInvalidate(t *testing.T) {
+	c := &FieldACLCache{}
+
+	load := func() (FieldACL, error) {
+		// Simulate another goroutine invalidating the cache while this
+		// load is in flight, before this Get gets a chance to commit it.
+		c.Invalidate()
+		return NewFieldACL([]FieldACLEntry{{RecordType: "stale"}}), nil
+	}
+	if _, err := c.Get(load); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	reloaded := false
+	freshLoad := func() (FieldACL, error) {
+		reloaded = true
+		return NewFieldACL([]FieldACLEntry{{RecordType: "fresh"}}), nil
+	}
+	if _, err := c.Get(freshLoad); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if !reloaded {
+		t.Error("Get served a cached value that was stale as of the generation it was loaded under")
+	}
+}
@@ -0,0 +1,57 @@
+package record
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The UserRole schemes a FieldACLEntry may target. _user_id, _role and
+// _field each carry an argument after the colon.
+const (
+	userRolePublic       = "_public"
+	userRoleAnyUser      = "_any_user"
+	userRoleOwner        = "_owner"
+	userRoleUserIDPrefix = "_user_id:"
+	userRoleRolePrefix   = "_role:"
+	userRoleFieldPrefix  = "_field:"
+)
+
+// FieldACLEntry is one rule granting or withholding access to a single
+// record field for principals matching UserRole.
+type FieldACLEntry struct {
+	RecordType   string `json:"record_type"`
+	RecordField  string `json:"record_field"`
+	UserRole     string `json:"user_role"`
+	Writable     bool   `json:"writable"`
+	Readable     bool   `json:"readable"`
+	Comparable   bool   `json:"comparable"`
+	Discoverable bool   `json:"discoverable"`
+}
+
+// Validate checks that e is well-formed: RecordType and RecordField are
+// present, and UserRole uses one of the supported schemes.
+func (e FieldACLEntry) Validate() error {
+	if e.RecordType == "" {
+		return fmt.Errorf("field_acl: record_type is required")
+	}
+	if e.RecordField == "" {
+		return fmt.Errorf("field_acl: record_field is required")
+	}
+	if !isValidUserRole(e.UserRole) {
+		return fmt.Errorf("field_acl: unsupported user_role %q", e.UserRole)
+	}
+	return nil
+}
+
+func isValidUserRole(userRole string) bool {
+	switch userRole {
+	case userRolePublic, userRoleAnyUser, userRoleOwner:
+		return true
+	}
+	for _, prefix := range []string{userRoleUserIDPrefix, userRoleRolePrefix, userRoleFieldPrefix} {
+		if strings.HasPrefix(userRole, prefix) && len(userRole) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
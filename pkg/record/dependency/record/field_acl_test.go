@@ -0,0 +1,140 @@
+package record
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+func TestFieldACLEntryMatches(t *testing.T) {
+	owner := &skydb.AuthInfo{ID: "owner-1"}
+	other := &skydb.AuthInfo{ID: "other-1", Roles: []string{"support"}}
+
+	tests := []struct {
+		name     string
+		entry    FieldACLEntry
+		authInfo *skydb.AuthInfo
+		row      skydb.Data
+		want     bool
+	}{
+		{"public matches anonymous", FieldACLEntry{UserRole: userRolePublic}, nil, nil, true},
+		{"any_user rejects anonymous", FieldACLEntry{UserRole: userRoleAnyUser}, nil, nil, false},
+		{"any_user matches any authenticated user", FieldACLEntry{UserRole: userRoleAnyUser}, other, nil, true},
+		{"owner matches the row's owner", FieldACLEntry{UserRole: userRoleOwner}, owner, skydb.Data{ownerIDField: "owner-1"}, true},
+		{"owner rejects a non-owner", FieldACLEntry{UserRole: userRoleOwner}, other, skydb.Data{ownerIDField: "owner-1"}, false},
+		{"owner rejects nil row", FieldACLEntry{UserRole: userRoleOwner}, owner, nil, false},
+		{"user_id matches the named user", FieldACLEntry{UserRole: userRoleUserIDPrefix + "owner-1"}, owner, nil, true},
+		{"user_id rejects another user", FieldACLEntry{UserRole: userRoleUserIDPrefix + "owner-1"}, other, nil, false},
+		{"role matches a held role", FieldACLEntry{UserRole: userRoleRolePrefix + "support"}, other, nil, true},
+		{"role rejects a role not held", FieldACLEntry{UserRole: userRoleRolePrefix + "support"}, owner, nil, false},
+		{"field matches when the row's field holds the user's ID", FieldACLEntry{UserRole: userRoleFieldPrefix + "assignee"}, owner, skydb.Data{"assignee": "owner-1"}, true},
+		{"field rejects a mismatched value", FieldACLEntry{UserRole: userRoleFieldPrefix + "assignee"}, owner, skydb.Data{"assignee": "someone-else"}, false},
+		{"field rejects nil row", FieldACLEntry{UserRole: userRoleFieldPrefix + "assignee"}, owner, nil, false},
+		{"unsupported scheme never matches", FieldACLEntry{UserRole: "_bogus"}, owner, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.matches(tt.authInfo, tt.row); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldACLFieldAccessDefaultsToFullAccessWhenUngoverned(t *testing.T) {
+	acl := NewFieldACL(nil)
+	access := acl.fieldAccess(nil, "note", "content", nil)
+	if access != fullAccess {
+		t.Errorf("fieldAccess() = %+v, want %+v", access, fullAccess)
+	}
+}
+
+func TestFieldACLFieldAccessUnionsMatchingEntries(t *testing.T) {
+	authInfo := &skydb.AuthInfo{ID: "owner-1"}
+	acl := NewFieldACL([]FieldACLEntry{
+		{RecordType: "note", RecordField: "content", UserRole: userRolePublic, Readable: true},
+		{RecordType: "note", RecordField: "content", UserRole: userRoleOwner, Writable: true, Comparable: true},
+	})
+
+	access := acl.fieldAccess(authInfo, "note", "content", skydb.Data{ownerIDField: "owner-1"})
+	if !access.Readable || !access.Writable || !access.Comparable || access.Discoverable {
+		t.Errorf("fieldAccess() = %+v, want union of both matching entries", access)
+	}
+}
+
+func TestFieldACLFieldAccessNoMatchIsNotFullAccess(t *testing.T) {
+	acl := NewFieldACL([]FieldACLEntry{
+		{RecordType: "note", RecordField: "content", UserRole: userRoleOwner, Readable: true},
+	})
+
+	// A governed field whose entries simply don't match this principal
+	// must end up with no access, not the ungoverned-field default.
+	access := acl.fieldAccess(&skydb.AuthInfo{ID: "stranger"}, "note", "content", skydb.Data{ownerIDField: "owner-1"})
+	if access != (fieldAccess{}) {
+		t.Errorf("fieldAccess() = %+v, want zero value", access)
+	}
+}
+
+func TestFieldACLCheckComparable(t *testing.T) {
+	acl := NewFieldACL([]FieldACLEntry{
+		{RecordType: "note", RecordField: "secret", UserRole: userRoleOwner, Comparable: false},
+	})
+
+	ownerPredicate := skydb.Predicate{
+		Operator: skydb.Equal,
+		Children: []interface{}{
+			skydb.Expression{Type: skydb.KeyPath, Value: "secret"},
+			skydb.Expression{Type: skydb.Literal, Value: "x"},
+		},
+	}
+
+	err := acl.CheckComparable(&skydb.AuthInfo{ID: "owner-1"}, "note", ownerPredicate)
+	if !errors.Is(err, ErrFieldNotComparable) {
+		t.Errorf("CheckComparable() = %v, want ErrFieldNotComparable", err)
+	}
+
+	discoverablePredicate := skydb.Predicate{
+		Operator: skydb.Equal,
+		Children: []interface{}{
+			skydb.Expression{Type: skydb.KeyPath, Value: "title"},
+			skydb.Expression{Type: skydb.Literal, Value: "x"},
+		},
+	}
+	if err := acl.CheckComparable(&skydb.AuthInfo{ID: "owner-1"}, "note", discoverablePredicate); err != nil {
+		t.Errorf("CheckComparable() on an ungoverned field = %v, want nil", err)
+	}
+}
+
+func TestFieldACLProjectExplicitKeysOmitsUnreadableFields(t *testing.T) {
+	acl := NewFieldACL([]FieldACLEntry{
+		{RecordType: "note", RecordField: "secret", UserRole: userRoleOwner, Readable: true},
+	})
+	row := skydb.Data{"title": "hello", "secret": "hunter2"}
+
+	got := acl.Project(&skydb.AuthInfo{ID: "stranger"}, "note", row, []string{"title", "secret"})
+
+	if _, ok := got["secret"]; ok {
+		t.Errorf("Project() = %+v, want secret omitted for a non-owner", got)
+	}
+	if got["title"] != "hello" {
+		t.Errorf("Project() = %+v, want title to pass through (ungoverned field defaults to readable)", got)
+	}
+}
+
+func TestFieldACLProjectWildcardOnlyIncludesDiscoverableFields(t *testing.T) {
+	acl := NewFieldACL([]FieldACLEntry{
+		{RecordType: "note", RecordField: "secret", UserRole: userRolePublic, Readable: true, Discoverable: false},
+	})
+	row := skydb.Data{"title": "hello", "secret": "hunter2"}
+
+	got := acl.Project(nil, "note", row, []string{WildcardKey})
+
+	if _, ok := got["secret"]; ok {
+		t.Errorf("Project() wildcard = %+v, want secret dropped (not discoverable)", got)
+	}
+	if got["title"] != "hello" {
+		t.Errorf("Project() wildcard = %+v, want title included (ungoverned field defaults to discoverable)", got)
+	}
+}
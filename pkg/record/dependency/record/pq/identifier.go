@@ -0,0 +1,22 @@
+package pq
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// identPattern is the set of characters recordStore ever treats as a
+// bare SQL identifier (a record type or a predicate's field name), both
+// of which come straight from the client's query request. Quoting alone
+// is not enough to make an arbitrary client-supplied string safe to
+// splice into generated SQL, so every such name is validated against
+// this allowlist before it ever reaches a query builder.
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateIdent returns an error if name is not a safe bare identifier.
+func validateIdent(kind, name string) error {
+	if !identPattern.MatchString(name) {
+		return fmt.Errorf("record: invalid %s %q", kind, name)
+	}
+	return nil
+}
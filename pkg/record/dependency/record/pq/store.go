@@ -1,44 +1,278 @@
 package pq
 
 import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
 	"github.com/sirupsen/logrus"
+
 	"github.com/skygeario/skygear-server/pkg/core/auth/role"
 	"github.com/skygeario/skygear-server/pkg/core/db"
 	"github.com/skygeario/skygear-server/pkg/record/dependency/record"
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
 )
 
 type recordStore struct {
 	roleStore role.Store
 
+	// canMigrate is carried for the bootstrap code that constructs a
+	// recordStore to call dialect.Migrate against before serving
+	// requests.
 	canMigrate bool
+	// dialect is used directly by queryRows to quote the record table
+	// name, since (unlike the fixed system tables the other stores in
+	// this series talk to) record table names are derived from a
+	// tenant-supplied record type and need per-engine identifier quoting.
+	dialect db.Dialect
 
 	sqlBuilder  db.SQLBuilder
 	sqlExecutor db.SQLExecutor
 	logger      *logrus.Entry
+
+	fieldACLCache *record.FieldACLCache
 }
 
 func newRecordStore(
 	roleStore role.Store,
 	canMigrate bool,
+	dialect db.Dialect,
 	sqlBuilder db.SQLBuilder,
 	sqlExecutor db.SQLExecutor,
 	logger *logrus.Entry,
+	fieldACLCache *record.FieldACLCache,
 ) *recordStore {
 	return &recordStore{
-		roleStore:   roleStore,
-		canMigrate:  canMigrate,
-		sqlBuilder:  sqlBuilder,
-		sqlExecutor: sqlExecutor,
-		logger:      logger,
+		roleStore:     roleStore,
+		canMigrate:    canMigrate,
+		dialect:       dialect,
+		sqlBuilder:    sqlBuilder,
+		sqlExecutor:   sqlExecutor,
+		logger:        logger,
+		fieldACLCache: fieldACLCache,
 	}
 }
 
+// NewRecordStore returns a record.Store backed by dialect (Postgres in
+// production; MySQL or SQLite for lighter-weight deployments and
+// tests). fieldACLCache is expected to be shared across requests for the
+// same tenant so its invalidate-on-write behaviour actually saves
+// repeated trips to the DB.
 func NewRecordStore(
 	roleStore role.Store,
 	canMigrate bool,
+	dialect db.Dialect,
 	sqlBuilder db.SQLBuilder,
 	sqlExecutor db.SQLExecutor,
 	logger *logrus.Entry,
+	fieldACLCache *record.FieldACLCache,
 ) record.Store {
-	return newRecordStore(roleStore, canMigrate, sqlBuilder, sqlExecutor, logger)
-}
\ No newline at end of file
+	return newRecordStore(roleStore, canMigrate, dialect, sqlBuilder, sqlExecutor, logger, fieldACLCache)
+}
+
+// GetFieldACL returns the tenant's Field ACL ruleset, served from cache
+// where possible.
+func (s *recordStore) GetFieldACL() (record.FieldACL, error) {
+	return s.fieldACLCache.Get(s.loadFieldACL)
+}
+
+func (s *recordStore) loadFieldACL() (record.FieldACL, error) {
+	rows, err := s.sqlExecutor.QueryWith(
+		s.sqlBuilder.Select(
+			"record_type", "record_field", "user_role",
+			"writable", "readable", "comparable", "discoverable",
+		).From(s.sqlBuilder.TableName("_field_access")),
+	)
+	if err != nil {
+		return record.FieldACL{}, err
+	}
+	defer rows.Close()
+
+	entries := []record.FieldACLEntry{}
+	for rows.Next() {
+		var e record.FieldACLEntry
+		if err := rows.Scan(
+			&e.RecordType, &e.RecordField, &e.UserRole,
+			&e.Writable, &e.Readable, &e.Comparable, &e.Discoverable,
+		); err != nil {
+			return record.FieldACL{}, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return record.FieldACL{}, err
+	}
+
+	return record.NewFieldACL(entries), nil
+}
+
+// SaveFieldACL atomically replaces the tenant's Field ACL ruleset: the
+// delete and the re-inserts run as part of the caller's transaction, so
+// a failure midway leaves the previous ruleset intact.
+func (s *recordStore) SaveFieldACL(acl record.FieldACL) error {
+	if _, err := s.sqlExecutor.ExecWith(
+		s.sqlBuilder.Delete(s.sqlBuilder.TableName("_field_access")),
+	); err != nil {
+		return err
+	}
+
+	for _, e := range acl.Entries() {
+		if _, err := s.sqlExecutor.ExecWith(
+			s.sqlBuilder.Insert(s.sqlBuilder.TableName("_field_access")).
+				Columns("record_type", "record_field", "user_role", "writable", "readable", "comparable", "discoverable").
+				Values(e.RecordType, e.RecordField, e.UserRole, e.Writable, e.Readable, e.Comparable, e.Discoverable),
+		); err != nil {
+			return err
+		}
+	}
+
+	s.fieldACLCache.Invalidate()
+	return nil
+}
+
+// Query runs query against the store, rejecting predicates over
+// non-comparable fields up front and projecting every returned row
+// through the tenant's Field ACL before handing it back.
+func (s *recordStore) Query(authInfo *skydb.AuthInfo, query record.Query) ([]skydb.Data, error) {
+	acl, err := s.GetFieldACL()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := acl.CheckComparable(authInfo, query.RecordType, query.Predicate); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queryRows(query)
+	if err != nil {
+		return nil, err
+	}
+
+	projected := make([]skydb.Data, len(rows))
+	for i, row := range rows {
+		projected[i] = acl.Project(authInfo, query.RecordType, row, query.DesiredKeys)
+	}
+	return projected, nil
+}
+
+func (s *recordStore) queryRows(query record.Query) ([]skydb.Data, error) {
+	rawTableName, err := recordTableName(query.RecordType)
+	if err != nil {
+		return nil, err
+	}
+	tableName := s.sqlBuilder.TableName(s.dialect.QuoteIdent(rawTableName))
+	builder := s.sqlBuilder.Select("*").From(tableName)
+
+	sqlPredicate, err := predicateToSQL(query.Predicate, s.dialect)
+	if err != nil {
+		return nil, err
+	}
+	if sqlPredicate != nil {
+		builder = builder.Where(sqlPredicate)
+	}
+
+	rows, err := s.sqlExecutor.QueryWith(builder)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRecordRows(rows)
+}
+
+func recordTableName(recordType string) (string, error) {
+	if err := validateIdent("record type", recordType); err != nil {
+		return "", err
+	}
+	return "record_" + recordType, nil
+}
+
+func scanRecordRows(rows *sql.Rows) ([]skydb.Data, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []skydb.Data{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := skydb.Data{}
+		for i, col := range columns {
+			row[col] = values[i]
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// predicateToSQL translates a skydb.Predicate into a squirrel Sqlizer.
+// It only understands the And/Equal shapes that skydb.AuthData produces
+// today; a richer dialect-aware translator is tracked for the DBAL work
+// following this. Every field name is validated as a bare identifier and
+// quoted via dialect before being handed to squirrel, which does not
+// quote or otherwise sanitize sq.Eq's map keys itself.
+func predicateToSQL(p skydb.Predicate, dialect db.Dialect) (sq.Sqlizer, error) {
+	if len(p.Children) == 0 {
+		return nil, nil
+	}
+
+	switch p.Operator {
+	case skydb.And:
+		conj := sq.And{}
+		for _, child := range p.Children {
+			childPredicate, ok := child.(skydb.Predicate)
+			if !ok {
+				return nil, fmt.Errorf("record: unsupported predicate child %T", child)
+			}
+			childSQL, err := predicateToSQL(childPredicate, dialect)
+			if err != nil {
+				return nil, err
+			}
+			if childSQL != nil {
+				conj = append(conj, childSQL)
+			}
+		}
+		return conj, nil
+	case skydb.Equal:
+		field, value, err := equalOperands(p.Children)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateIdent("field", field); err != nil {
+			return nil, err
+		}
+		return sq.Eq{dialect.QuoteIdent(field): value}, nil
+	default:
+		return nil, fmt.Errorf("record: unsupported predicate operator %v", p.Operator)
+	}
+}
+
+func equalOperands(children []interface{}) (string, interface{}, error) {
+	if len(children) != 2 {
+		return "", nil, fmt.Errorf("record: equal predicate requires 2 operands")
+	}
+
+	keyExpr, ok := children[0].(skydb.Expression)
+	if !ok || keyExpr.Type != skydb.KeyPath {
+		return "", nil, fmt.Errorf("record: equal predicate's first operand must be a key path")
+	}
+	field, ok := keyExpr.Value.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("record: key path value must be a string")
+	}
+
+	valueExpr, ok := children[1].(skydb.Expression)
+	if !ok || valueExpr.Type != skydb.Literal {
+		return "", nil, fmt.Errorf("record: equal predicate's second operand must be a literal")
+	}
+
+	return field, valueExpr.Value, nil
+}
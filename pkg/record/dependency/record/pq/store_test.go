@@ -0,0 +1,90 @@
+package pq
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+// fakeDialect is a minimal db.Dialect stand-in so predicateToSQL and
+// recordTableName can be tested without pulling in a real SQL engine.
+type fakeDialect struct{}
+
+func (fakeDialect) Name() string                                  { return "fake" }
+func (fakeDialect) Placeholder(n int) string                      { return "?" }
+func (fakeDialect) JSONExtract(column, _ string) string           { return column }
+func (fakeDialect) UpsertClause(_ []string, _ []string) string    { return "" }
+func (fakeDialect) Migrate(context.Context, *sql.DB, fs.FS) error { return nil }
+func (fakeDialect) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func equalPredicate(field string, value interface{}) skydb.Predicate {
+	return skydb.Predicate{
+		Operator: skydb.Equal,
+		Children: []interface{}{
+			skydb.Expression{Type: skydb.KeyPath, Value: field},
+			skydb.Expression{Type: skydb.Literal, Value: value},
+		},
+	}
+}
+
+func TestRecordTableNameRejectsNonIdentifierRecordType(t *testing.T) {
+	malicious := `x" UNION SELECT id, password_hash FROM "_auth_info" --`
+	if _, err := recordTableName(malicious); err == nil {
+		t.Errorf("recordTableName(%q) = nil error, want rejection", malicious)
+	}
+}
+
+func TestRecordTableNameAcceptsValidIdentifier(t *testing.T) {
+	got, err := recordTableName("note")
+	if err != nil {
+		t.Fatalf("recordTableName(\"note\") = %v, want nil error", err)
+	}
+	if got != "record_note" {
+		t.Errorf("recordTableName(\"note\") = %q, want \"record_note\"", got)
+	}
+}
+
+func TestPredicateToSQLRejectsInjectionInFieldName(t *testing.T) {
+	p := equalPredicate(`x = 1 OR '1'='1`, "v")
+	if _, err := predicateToSQL(p, fakeDialect{}); err == nil {
+		t.Error("predicateToSQL with an injected field name = nil error, want rejection")
+	}
+}
+
+func TestPredicateToSQLQuotesFieldName(t *testing.T) {
+	p := equalPredicate("title", "hello")
+	sqlizer, err := predicateToSQL(p, fakeDialect{})
+	if err != nil {
+		t.Fatalf("predicateToSQL: %v", err)
+	}
+
+	query, args, err := sqlizer.ToSql()
+	if err != nil {
+		t.Fatalf("ToSql: %v", err)
+	}
+	if !strings.Contains(query, `"title"`) {
+		t.Errorf("ToSql() query = %q, want the field name quoted", query)
+	}
+	if len(args) != 1 || args[0] != "hello" {
+		t.Errorf("ToSql() args = %v, want [hello]", args)
+	}
+}
+
+func TestPredicateToSQLAndRejectsInjectionInNestedChild(t *testing.T) {
+	p := skydb.Predicate{
+		Operator: skydb.And,
+		Children: []interface{}{
+			equalPredicate("title", "hello"),
+			equalPredicate(`secret"; DROP TABLE record_note; --`, "x"),
+		},
+	}
+	if _, err := predicateToSQL(p, fakeDialect{}); err == nil {
+		t.Error("predicateToSQL with an injected nested field name = nil error, want rejection")
+	}
+}
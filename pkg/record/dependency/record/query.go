@@ -0,0 +1,15 @@
+package record
+
+import "github.com/skygeario/skygear-server/pkg/server/skydb"
+
+// WildcardKey is the DesiredKeys sentinel meaning "every field the
+// requesting AuthInfo may discover", as opposed to an explicit list.
+const WildcardKey = "*"
+
+// Query describes a record query before Field ACL projection narrows it
+// down to what the requesting AuthInfo may actually see.
+type Query struct {
+	RecordType  string
+	DesiredKeys []string
+	Predicate   skydb.Predicate
+}
@@ -0,0 +1,23 @@
+// Package record implements the record gear's database abstraction:
+// querying records subject to Field ACL projection, and managing the
+// Field ACL ruleset itself.
+package record
+
+import (
+	"github.com/skygeario/skygear-server/pkg/server/skydb"
+)
+
+// Store is the record gear's database abstraction.
+type Store interface {
+	// Query runs query against the store on behalf of authInfo (nil for
+	// an unauthenticated request), projecting each row through the
+	// tenant's Field ACL before returning it.
+	Query(authInfo *skydb.AuthInfo, query Query) ([]skydb.Data, error)
+
+	// GetFieldACL returns the tenant's current Field ACL ruleset, served
+	// from cache where possible.
+	GetFieldACL() (FieldACL, error)
+
+	// SaveFieldACL atomically replaces the tenant's Field ACL ruleset.
+	SaveFieldACL(acl FieldACL) error
+}
@@ -0,0 +1,16 @@
+// Package record exposes the /schema/* and /record/* surface backed by
+// the record gear's Store, including Field ACL administration.
+package record
+
+import "net/http"
+
+// DependencyMap provides the record gear's handlers with their concrete
+// dependencies (stores, logger, ...) by name, mirroring auth.DependencyMap
+// and admin.DependencyMap in the other gears.
+//
+// No concrete implementation is wired up yet: see the equivalent note on
+// admin.DependencyMap. pq.NewRecordStore and db.NewSQLBuilder/db.NewConn
+// are the pieces such wiring would assemble.
+type DependencyMap interface {
+	Provide(name string, request *http.Request) interface{}
+}
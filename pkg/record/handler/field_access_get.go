@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz"
+	"github.com/skygeario/skygear-server/pkg/core/auth/authz/policy"
+	"github.com/skygeario/skygear-server/pkg/core/db"
+	"github.com/skygeario/skygear-server/pkg/core/handler"
+	"github.com/skygeario/skygear-server/pkg/core/inject"
+	"github.com/skygeario/skygear-server/pkg/core/server"
+	recordGear "github.com/skygeario/skygear-server/pkg/record"
+	"github.com/skygeario/skygear-server/pkg/record/dependency/record"
+)
+
+func AttachFieldAccessGetHandler(
+	server *server.Server,
+	recordDependency recordGear.DependencyMap,
+) *server.Server {
+	server.Handle("/schema/field_access/get", &FieldAccessGetHandlerFactory{
+		recordDependency,
+	}).Methods("POST")
+	return server
+}
+
+type FieldAccessGetHandlerFactory struct {
+	Dependency recordGear.DependencyMap
+}
+
+func (f FieldAccessGetHandlerFactory) NewHandler(request *http.Request) http.Handler {
+	h := &FieldAccessGetHandler{}
+	inject.DefaultInject(h, f.Dependency, request)
+	return handler.APIHandlerToHandler(h, h.TxContext)
+}
+
+func (f FieldAccessGetHandlerFactory) ProvideAuthzPolicy() authz.Policy {
+	return policy.AnyOf(
+		authz.PolicyFunc(policy.RequireMasterKey),
+		authz.PolicyFunc(policy.RequireAdminRole),
+	)
+}
+
+type FieldAccessGetRequestPayload struct {
+}
+
+func (s FieldAccessGetRequestPayload) Validate() error {
+	return nil
+}
+
+// FieldAccessGetHandler fetches the entire Field ACL settings.
+//
+//	curl -X POST -H "Content-Type: application/json" \
+//	  http://localhost:3000/schema/field_access/get
+type FieldAccessGetHandler struct {
+	TxContext   db.TxContext `dependency:"TxContext"`
+	RecordStore record.Store `dependency:"RecordStore"`
+}
+
+func (h FieldAccessGetHandler) WithTx() bool {
+	return false
+}
+
+func (h FieldAccessGetHandler) DecodeRequest(request *http.Request) (handler.RequestPayload, error) {
+	return FieldAccessGetRequestPayload{}, nil
+}
+
+func (h FieldAccessGetHandler) Handle(req interface{}) (resp interface{}, err error) {
+	acl, err := h.RecordStore.GetFieldACL()
+	if err != nil {
+		return nil, err
+	}
+
+	return FieldAccessResponse{Access: acl.Entries()}, nil
+}
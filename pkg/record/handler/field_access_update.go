@@ -36,20 +36,31 @@ func (f FieldAccessUpdateHandlerFactory) NewHandler(request *http.Request) http.
 }
 
 func (f FieldAccessUpdateHandlerFactory) ProvideAuthzPolicy() authz.Policy {
-	return policy.AllOf(
+	return policy.AnyOf(
 		authz.PolicyFunc(policy.RequireMasterKey),
+		authz.PolicyFunc(policy.RequireAdminRole),
 	)
 }
 
 type FieldAccessUpdateRequestPayload struct {
+	Access []record.FieldACLEntry `json:"access"`
 }
 
 func (s FieldAccessUpdateRequestPayload) Validate() error {
+	for _, entry := range s.Access {
+		if err := entry.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+type FieldAccessResponse struct {
+	Access []record.FieldACLEntry `json:"access"`
+}
+
 /*
-FieldAccessUpdateHandler fetches the entire Field ACL settings.
+FieldAccessUpdateHandler replaces the entire Field ACL settings.
 curl -X POST -H "Content-Type: application/json" \
   -d @- http://localhost:3000/schema/field_access/update <<EOF
 {
@@ -87,5 +98,12 @@ func (h FieldAccessUpdateHandler) DecodeRequest(request *http.Request) (handler.
 }
 
 func (h FieldAccessUpdateHandler) Handle(req interface{}) (resp interface{}, err error) {
-	return
+	payload := req.(FieldAccessUpdateRequestPayload)
+
+	acl := record.NewFieldACL(payload.Access)
+	if err := h.RecordStore.SaveFieldACL(acl); err != nil {
+		return nil, err
+	}
+
+	return FieldAccessResponse{Access: acl.Entries()}, nil
 }